@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+// accessibilityReportInput is the input schema for the get_accessibility_report tool.
+type accessibilityReportInput struct {
+	URL      string                         `json:"url"`
+	Strategy string                         `json:"strategy,omitempty"`
+	Baseline *pagespeed.AccessibilityReport `json:"baseline,omitempty"`
+}
+
+type accessibilityReportResponse struct {
+	InputURL string                         `json:"inputUrl"`
+	FinalURL string                         `json:"finalUrl,omitempty"`
+	Strategy string                         `json:"strategy"`
+	Report   *pagespeed.AccessibilityReport `json:"report"`
+	Diff     *pagespeed.AccessibilityDiff   `json:"diff,omitempty"`
+}
+
+// getAccessibilityReport runs the Lighthouse accessibility category for one
+// URL and groups its failures by WCAG success criterion, diffing against a
+// baseline report when one is supplied.
+func getAccessibilityReport(
+	ctx context.Context,
+	client pageAnalyzer,
+	input accessibilityReportInput,
+) (*mcp.CallToolResult, any, error) {
+	result, failure, err := analyzeLabCategories(
+		ctx,
+		client,
+		input.URL,
+		input.Strategy,
+		[]string{"accessibility"},
+		"get_accessibility_report",
+	)
+	if err != nil || failure != nil {
+		return failure, nil, err
+	}
+	report, err := pagespeed.ParseAccessibilityReport(result.LabData)
+	if err != nil {
+		return auditUnavailableResult(result, err)
+	}
+
+	response := accessibilityReportResponse{
+		InputURL: result.Metadata.InputURL,
+		FinalURL: result.Metadata.FinalURL,
+		Strategy: result.Metadata.Strategy,
+		Report:   report,
+	}
+	if input.Baseline != nil {
+		response.Diff = pagespeed.DiffAccessibilityReports(input.Baseline, report)
+	}
+	return jsonToolResult(response)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+func TestGetAccessibilityReport_GroupsFailuresAndDiffsBaseline(t *testing.T) {
+	t.Parallel()
+
+	baseline := &pagespeed.AccessibilityReport{
+		BestPractices: []pagespeed.AccessibilityAudit{
+			{ID: "tabindex", Nodes: []pagespeed.FailingNode{{Selector: "div[tabindex]"}}},
+		},
+	}
+	result, _, err := getAccessibilityReport(context.Background(), fixtureAnalyzer{t: t}, accessibilityReportInput{
+		URL:      "https://example.test/page",
+		Baseline: baseline,
+	})
+	if err != nil {
+		t.Fatalf("getAccessibilityReport: %v", err)
+	}
+	response := decodeToolText[accessibilityReportResponse](t, result)
+	report := response.Report
+	if report.FailingAudits != 3 || len(report.Criteria) != 2 || report.Criteria[1].ID != "1.4.3" {
+		t.Errorf("report = %+v", report)
+	}
+	if response.Diff == nil || len(response.Diff.Fixed) != 1 || response.Diff.Fixed[0].ID != "tabindex" ||
+		len(response.Diff.Regressed) != 3 {
+		t.Errorf("diff = %+v", response.Diff)
+	}
+}
+
+func TestGetAccessibilityReport_OmitsDiffWithoutBaseline(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := getAccessibilityReport(context.Background(), fixtureAnalyzer{t: t}, accessibilityReportInput{
+		URL: "https://example.test/page",
+	})
+	if err != nil {
+		t.Fatalf("getAccessibilityReport: %v", err)
+	}
+	if response := decodeToolText[accessibilityReportResponse](t, result); response.Diff != nil {
+		t.Errorf("diff = %+v, want omitted", response.Diff)
+	}
+}
+
+func TestGetAccessibilityReport_MissingAudit_ReturnsStructuredToolError(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := getAccessibilityReport(context.Background(), emptyLabAnalyzer{}, accessibilityReportInput{
+		URL: "https://example.test/page",
+	})
+	assertAuditUnavailable(t, result, err)
+}
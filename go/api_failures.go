@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/apihttp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+)
+
+var lighthouseErrorPattern = regexp.MustCompile(`Lighthouse returned error: ([A-Z][A-Z0-9_]*)`)
+
+// apiFailure is the transport-independent classification of an upstream failure.
+type apiFailure struct {
+	Code      string
+	Message   string
+	Reason    string
+	Retryable bool
+	Hint      string
+}
+
+// describeAPIFailure maps Google API, Lighthouse, and network errors to stable
+// failure codes with remediation hints.
+func describeAPIFailure(err error) apiFailure {
+	failure := apiFailure{
+		Code:    "request_failed",
+		Message: err.Error(),
+	}
+
+	var statusError *apihttp.StatusError
+	if errors.As(err, &statusError) {
+		failure.Reason = statusError.Reason
+		failure.Retryable = statusError.Retryable()
+		classifyStatusError(statusError, &failure)
+		return failure
+	}
+
+	var networkError net.Error
+	if errors.Is(err, context.DeadlineExceeded) ||
+		(errors.As(err, &networkError) && networkError.Timeout()) {
+		failure.Code = "timeout"
+		failure.Retryable = true
+		failure.Hint = "The upstream request timed out; retry later or analyze fewer URLs at once."
+	}
+	return failure
+}
+
+func classifyStatusError(statusError *apihttp.StatusError, failure *apiFailure) {
+	service := statusError.Metadata["service"]
+	if service == "" {
+		service = "the " + statusError.Service
+	}
+
+	if match := lighthouseErrorPattern.FindStringSubmatch(statusError.Message); match != nil {
+		failure.Reason = match[1]
+		switch match[1] {
+		case "FAILED_DOCUMENT_REQUEST",
+			"ERRORED_DOCUMENT_REQUEST",
+			"DNS_FAILURE",
+			"INSECURE_DOCUMENT_REQUEST",
+			"NOT_HTML":
+			failure.Code = "url_unreachable"
+			failure.Retryable = false
+			failure.Hint = "Lighthouse could not load the page. Confirm the URL is public, " +
+				"returns HTML with HTTP 200, and is not blocking Google's crawlers."
+		default:
+			failure.Code = "lighthouse_failed"
+			failure.Hint = "Lighthouse could not complete the run. Retry later; " +
+				"persistent failures usually indicate a page that never paints or hangs."
+		}
+		return
+	}
+
+	switch statusError.Reason {
+	case "SERVICE_DISABLED", "accessNotConfigured":
+		failure.Code = "api_not_enabled"
+		failure.Retryable = false
+		failure.Hint = fmt.Sprintf("Enable %s in the API key's Google Cloud project.", service)
+		if activationURL := statusError.Metadata["activationUrl"]; activationURL != "" {
+			failure.Hint += " Activation URL: " + activationURL
+		}
+		return
+	case "API_KEY_INVALID", "keyInvalid":
+		failure.Code = "api_key_invalid"
+		failure.Retryable = false
+		failure.Hint = "The configured API key is invalid. Check --api-key, " +
+			"GOOGLE_PSI_API_KEY, or the .env file."
+		return
+	case "API_KEY_SERVICE_BLOCKED",
+		"API_KEY_HTTP_REFERRER_BLOCKED",
+		"API_KEY_IP_ADDRESS_BLOCKED",
+		"API_KEY_ANDROID_APP_BLOCKED",
+		"API_KEY_IOS_APP_BLOCKED",
+		"ipRefererBlocked":
+		failure.Code = "api_key_restricted"
+		failure.Retryable = false
+		failure.Hint = fmt.Sprintf(
+			"The API key's restrictions block this request. Allow %s in the key's API restrictions.",
+			service,
+		)
+		return
+	case "RATE_LIMIT_EXCEEDED", "rateLimitExceeded", "userRateLimitExceeded":
+		failure.Code = "rate_limited"
+		failure.Retryable = true
+		failure.Hint = "The per-minute quota was exceeded. Wait before retrying."
+		return
+	case "dailyLimitExceeded", "quotaExceeded":
+		failure.Code = "quota_exceeded"
+		failure.Retryable = false
+		failure.Hint = "The project's daily quota is exhausted. Retry after the quota resets " +
+			"or request a higher quota."
+		return
+	}
+
+	switch {
+	case crux.IsNotFound(statusError):
+		failure.Code = "no_field_data"
+		failure.Retryable = false
+		failure.Hint = "CrUX has no record for this target and form factor. " +
+			"Try the origin, form_factor all, or a higher-traffic page."
+	case statusError.Status == "RESOURCE_EXHAUSTED" ||
+		statusError.StatusCode == http.StatusTooManyRequests:
+		failure.Code = "rate_limited"
+		failure.Hint = "The API quota was exceeded. Wait before retrying."
+	case statusError.StatusCode >= 500:
+		failure.Code = "upstream_unavailable"
+		failure.Hint = "The Google API is temporarily unavailable. Retry later."
+	case statusError.Status == "PERMISSION_DENIED":
+		failure.Code = "permission_denied"
+		failure.Hint = fmt.Sprintf(
+			"The API key is not allowed to call %s. Check that the API is enabled "+
+				"and allowed in the key's restrictions.",
+			service,
+		)
+	case statusError.Status == "INVALID_ARGUMENT" &&
+		strings.Contains(strings.ToLower(statusError.Message), "api key"):
+		failure.Code = "api_key_invalid"
+		failure.Hint = "The configured API key was rejected. Check --api-key, " +
+			"GOOGLE_PSI_API_KEY, or the .env file."
+	default:
+		failure.Code = "upstream_rejected"
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+)
+
+// cruxBatchInput is the input schema for the get_crux_data_batch tool.
+type cruxBatchInput struct {
+	Targets     []string  `json:"targets"`
+	TargetType  string    `json:"target_type,omitempty"`
+	FormFactors []string  `json:"form_factors,omitempty"`
+	Metrics     []string  `json:"metrics,omitempty"`
+	Fallback    bool      `json:"fallback,omitempty"`
+	Percentiles []float64 `json:"percentiles,omitempty"`
+}
+
+type cruxBatchResponse struct {
+	Results []*crux.Result `json:"results"`
+	Errors  []cruxFailure  `json:"errors"`
+}
+
+// queryCruxBatch runs current CrUX queries for every target and form factor,
+// returning successful results alongside structured per-query failures.
+func queryCruxBatch(
+	ctx context.Context,
+	cruxClient cruxQuerier,
+	input cruxBatchInput,
+) (*mcp.CallToolResult, any, error) {
+	if len(input.Targets) == 0 {
+		return nil, nil, fmt.Errorf("at least one target is required")
+	}
+	if len(input.Targets) > maxBatchCruxTargets {
+		return nil, nil, fmt.Errorf("at most %d targets may be queried per call", maxBatchCruxTargets)
+	}
+
+	percentiles, err := crux.NormalizePercentiles(input.Percentiles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	formFactors := normalizeFormFactors(input.FormFactors)
+
+	type queryKey struct {
+		target     string
+		formFactor string
+	}
+
+	requests := make([]crux.QueryRequest, 0, len(input.Targets)*len(formFactors))
+	seen := make(map[queryKey]struct{}, cap(requests))
+	for _, target := range input.Targets {
+		for _, formFactor := range formFactors {
+			request, err := crux.NewQueryRequest(
+				target,
+				input.TargetType,
+				formFactor,
+				input.Metrics,
+				0,
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			key := queryKey{target: request.Target, formFactor: request.FormFactor}
+			if _, duplicate := seen[key]; duplicate {
+				continue
+			}
+			seen[key] = struct{}{}
+			request.OriginFallback = input.Fallback
+			requests = append(requests, request)
+		}
+	}
+
+	type cruxEntry struct {
+		result  *crux.Result
+		failure *cruxFailure
+	}
+
+	entries := make([]cruxEntry, len(requests))
+	var waitGroup sync.WaitGroup
+	for index, request := range requests {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			result, err := cruxClient.QueryCurrent(ctx, request)
+			if err != nil {
+				slog.Warn(
+					"CrUX query failed",
+					"target",
+					request.Target,
+					"formFactor",
+					request.FormFactor,
+					"err",
+					err,
+				)
+				failure := classifyCruxFailure(request, err)
+				entries[index].failure = &failure
+				return
+			}
+			result.EstimatePercentiles(percentiles)
+			entries[index].result = result
+		}()
+	}
+	waitGroup.Wait()
+
+	if ctx.Err() != nil {
+		return nil, nil, ctx.Err()
+	}
+
+	response := cruxBatchResponse{
+		Results: make([]*crux.Result, 0, len(entries)),
+		Errors:  make([]cruxFailure, 0),
+	}
+	for _, entry := range entries {
+		if entry.result != nil {
+			response.Results = append(response.Results, entry.result)
+		}
+		if entry.failure != nil {
+			response.Errors = append(response.Errors, *entry.failure)
+		}
+	}
+
+	return jsonToolResult(response)
+}
+
+// normalizeFormFactors lowercases and dedupes form factors, treating an
+// empty list or a blank entry as all.
+func normalizeFormFactors(values []string) []string {
+	if len(values) == 0 {
+		return []string{"all"}
+	}
+	result := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			value = "all"
+		}
+		if _, duplicate := seen[value]; duplicate {
+			continue
+		}
+		seen[value] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
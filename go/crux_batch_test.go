@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/apihttp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+)
+
+type selectiveCruxQuerier struct {
+	fakeCruxQuerier
+}
+
+func (q selectiveCruxQuerier) QueryCurrent(
+	ctx context.Context,
+	request crux.QueryRequest,
+) (*crux.Result, error) {
+	if strings.Contains(request.Target, "quiet") && request.FormFactor == "desktop" {
+		return nil, &apihttp.StatusError{
+			Service:    "CrUX API",
+			StatusCode: http.StatusNotFound,
+			Status:     "NOT_FOUND",
+			Message:    "chrome ux report data not found",
+		}
+	}
+	result, err := q.fakeCruxQuerier.QueryCurrent(ctx, request)
+	if err == nil {
+		result.FormFactor = request.FormFactor
+	}
+	return result, err
+}
+
+func TestQueryCruxBatch_ExpandsTargetsAndFormFactorsWithPartialFailures(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := queryCruxBatch(context.Background(), selectiveCruxQuerier{}, cruxBatchInput{
+		Targets:     []string{"https://example.test/busy", "https://example.test/quiet"},
+		FormFactors: []string{"phone", "DESKTOP", "phone"},
+	})
+	if err != nil {
+		t.Fatalf("queryCruxBatch: %v", err)
+	}
+
+	response := decodeToolText[cruxBatchResponse](t, result)
+	if len(response.Results) != 3 {
+		t.Errorf("results = %d, want 3", len(response.Results))
+	}
+	if len(response.Errors) != 1 {
+		t.Fatalf("errors = %d, want 1", len(response.Errors))
+	}
+	failure := response.Errors[0]
+	if failure.Code != "no_field_data" || failure.FormFactor != "desktop" {
+		t.Errorf("failure = %+v, want desktop no_field_data", failure)
+	}
+	if response.Results[0].Target != "https://example.test/busy" ||
+		response.Results[0].FormFactor != "phone" {
+		t.Errorf("first result = %+v, want request order preserved", response.Results[0])
+	}
+}
+
+func TestQueryCruxBatch_DedupesTargetsAndBlankFormFactors(t *testing.T) {
+	t.Parallel()
+
+	querier := &trackingCruxQuerier{}
+	result, _, err := queryCruxBatch(context.Background(), querier, cruxBatchInput{
+		Targets:     []string{"https://example.test/page", " https://example.test/page "},
+		FormFactors: []string{"", "ALL", "phone"},
+	})
+	if err != nil {
+		t.Fatalf("queryCruxBatch: %v", err)
+	}
+
+	response := decodeToolText[cruxBatchResponse](t, result)
+	if len(response.Results) != 2 {
+		t.Errorf("results = %d, want all and phone for one target", len(response.Results))
+	}
+	if calls := querier.calls.Load(); calls != 2 {
+		t.Errorf("API calls = %d, want 2", calls)
+	}
+}
+
+func TestQueryCruxBatch_RejectsInvalidInputBeforeCallingAPI(t *testing.T) {
+	t.Parallel()
+
+	querier := &trackingCruxQuerier{}
+	targets := make([]string, maxBatchCruxTargets+1)
+	for index := range targets {
+		targets[index] = "https://example.test"
+	}
+
+	for _, input := range []cruxBatchInput{
+		{},
+		{Targets: targets},
+		{Targets: []string{"https://example.test"}, FormFactors: []string{"watch"}},
+	} {
+		if _, _, err := queryCruxBatch(context.Background(), querier, input); err == nil {
+			t.Errorf("queryCruxBatch(%+v) returned nil error", input)
+		}
+	}
+	if calls := querier.calls.Load(); calls != 0 {
+		t.Errorf("API calls = %d, want 0", calls)
+	}
+}
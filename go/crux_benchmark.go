@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+)
+
+const (
+	maxBenchmarkCompetitors = 9
+	defaultTrendPeriods     = 6
+)
+
+// benchmarkOriginsInput is the input schema for the benchmark_origins tool.
+type benchmarkOriginsInput struct {
+	Origin       string   `json:"origin"`
+	Competitors  []string `json:"competitors"`
+	FormFactor   string   `json:"form_factor,omitempty"`
+	TrendPeriods int      `json:"trend_periods,omitempty"`
+}
+
+type benchmarkResponse struct {
+	Benchmark *crux.Benchmark `json:"benchmark"`
+	Errors    []cruxFailure   `json:"errors"`
+}
+
+// benchmarkOrigins queries current and historical CrUX data for the caller's
+// origin and its competitors and ranks them per Core Web Vital.
+func benchmarkOrigins(
+	ctx context.Context,
+	cruxClient cruxQuerier,
+	input benchmarkOriginsInput,
+) (*mcp.CallToolResult, any, error) {
+	if len(input.Competitors) == 0 {
+		return nil, nil, fmt.Errorf("at least one competitor origin is required")
+	}
+	if len(input.Competitors) > maxBenchmarkCompetitors {
+		return nil, nil, fmt.Errorf("at most %d competitor origins may be benchmarked per call", maxBenchmarkCompetitors)
+	}
+	trendPeriods := input.TrendPeriods
+	if trendPeriods == 0 {
+		trendPeriods = defaultTrendPeriods
+	}
+	if trendPeriods < 2 || trendPeriods > 40 {
+		return nil, nil, fmt.Errorf("trend_periods must be between 2 and 40")
+	}
+
+	requests := make([]crux.QueryRequest, 0, len(input.Competitors)+1)
+	seen := make(map[string]struct{}, len(input.Competitors)+1)
+	for _, origin := range append([]string{input.Origin}, input.Competitors...) {
+		request, err := crux.NewQueryRequest(
+			origin,
+			"origin",
+			input.FormFactor,
+			crux.CoreWebVitals,
+			trendPeriods,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, duplicate := seen[request.Target]; duplicate {
+			continue
+		}
+		seen[request.Target] = struct{}{}
+		requests = append(requests, request)
+	}
+
+	type benchmarkEntry struct {
+		input    crux.BenchmarkInput
+		failures []cruxFailure
+	}
+
+	entries := make([]benchmarkEntry, len(requests))
+	var waitGroup sync.WaitGroup
+	for index, request := range requests {
+		entries[index].input = crux.BenchmarkInput{
+			Origin:  request.Target,
+			Primary: index == 0,
+		}
+		waitGroup.Add(2)
+		var mutex sync.Mutex
+		recordFailure := func(err error) {
+			slog.Warn("CrUX benchmark query failed", "target", request.Target, "err", err)
+			failure := classifyCruxFailure(request, err)
+			mutex.Lock()
+			entries[index].failures = append(entries[index].failures, failure)
+			mutex.Unlock()
+		}
+		go func() {
+			defer waitGroup.Done()
+			result, err := cruxClient.QueryCurrent(ctx, request)
+			if err != nil {
+				recordFailure(err)
+				return
+			}
+			entries[index].input.Current = result
+		}()
+		go func() {
+			defer waitGroup.Done()
+			result, err := cruxClient.QueryHistory(ctx, request)
+			if err != nil {
+				recordFailure(err)
+				return
+			}
+			entries[index].input.History = result
+		}()
+	}
+	waitGroup.Wait()
+
+	if ctx.Err() != nil {
+		return nil, nil, ctx.Err()
+	}
+
+	inputs := make([]crux.BenchmarkInput, 0, len(entries))
+	response := benchmarkResponse{Errors: make([]cruxFailure, 0)}
+	for _, entry := range entries {
+		inputs = append(inputs, entry.input)
+		response.Errors = append(response.Errors, entry.failures...)
+	}
+	response.Benchmark = crux.NewBenchmark(inputs, trendPeriods)
+	return jsonToolResult(response)
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBenchmarkOrigins_RejectsInvalidInputBeforeCallingAPI(t *testing.T) {
+	t.Parallel()
+
+	querier := &trackingCruxQuerier{}
+	for _, input := range []benchmarkOriginsInput{
+		{Origin: "https://example.test"},
+		{Origin: "https://example.test/path", Competitors: []string{"https://rival.test"}},
+		{Origin: "https://example.test", Competitors: []string{"https://rival.test"}, TrendPeriods: 41},
+	} {
+		if _, _, err := benchmarkOrigins(context.Background(), querier, input); err == nil {
+			t.Errorf("benchmarkOrigins(%+v) returned nil error", input)
+		}
+	}
+	if calls := querier.calls.Load(); calls != 0 {
+		t.Errorf("API calls = %d, want 0", calls)
+	}
+}
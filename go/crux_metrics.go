@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+)
+
+const (
+	cruxMetricURIPrefix   = "crux://metrics/"
+	cruxMetricURITemplate = cruxMetricURIPrefix + "{metric}"
+)
+
+// listCruxMetricsInput is the input schema for the list_crux_metrics tool.
+type listCruxMetricsInput struct{}
+
+type cruxMetricsResponse struct {
+	Metrics []crux.MetricInfo `json:"metrics"`
+}
+
+// listCruxMetrics returns the catalog of known CrUX metrics and their aliases.
+func listCruxMetrics() (*mcp.CallToolResult, any, error) {
+	return jsonToolResult(cruxMetricsResponse{Metrics: crux.Metrics()})
+}
+
+// readCruxMetric serves one catalog entry from the crux://metrics/{metric}
+// resource template.
+func readCruxMetric(
+	_ context.Context,
+	request *mcp.ReadResourceRequest,
+) (*mcp.ReadResourceResult, error) {
+	uri := request.Params.URI
+	info, ok := crux.LookupMetric(strings.TrimPrefix(uri, cruxMetricURIPrefix))
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	encoded, err := json.Marshal(info)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling CrUX metric: %w", err)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: uri, MIMEType: "application/json", Text: string(encoded)},
+		},
+	}, nil
+}
+
+// completeCruxMetric completes CrUX metric names and aliases. MCP completion
+// applies to prompt and resource-template arguments, so clients reach it
+// through the crux://metrics/{metric} template; the metrics argument name used
+// by the CrUX tools is accepted as well.
+func completeCruxMetric(
+	_ context.Context,
+	request *mcp.CompleteRequest,
+) (*mcp.CompleteResult, error) {
+	values := []string{}
+	switch request.Params.Argument.Name {
+	case "metric", "metrics":
+		values = crux.CompleteMetricNames(request.Params.Argument.Value)
+	}
+	return &mcp.CompleteResult{
+		Completion: mcp.CompletionResultDetails{
+			Values: values,
+			Total:  len(values),
+		},
+	}, nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCruxMetricCatalog_ServesCompletionAndResource(t *testing.T) {
+	t.Parallel()
+
+	srv := newServer(&trackingAnalyzer{}, fakeCruxQuerier{})
+	ctx := context.Background()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := srv.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server.Connect: %v", err)
+	}
+	defer serverSession.Close()
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, nil)
+	clientSession, err := mcpClient.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client.Connect: %v", err)
+	}
+	defer clientSession.Close()
+
+	completion, err := clientSession.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/resource", URI: cruxMetricURITemplate},
+		Argument: mcp.CompleteParamsArgument{Name: "metric", Value: "in"},
+	})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if want := []string{"inp", "interaction_to_next_paint"}; !slices.Equal(completion.Completion.Values, want) {
+		t.Errorf("completions = %v, want %v", completion.Completion.Values, want)
+	}
+
+	resource, err := clientSession.ReadResource(ctx, &mcp.ReadResourceParams{URI: cruxMetricURIPrefix + "cls"})
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if len(resource.Contents) != 1 ||
+		!strings.Contains(resource.Contents[0].Text, `"name":"cumulative_layout_shift"`) {
+		t.Errorf("resource = %+v, want CLS catalog entry", resource.Contents)
+	}
+}
+
+func TestQueryCruxCurrent_RejectsUnknownMetricBeforeCallingAPI(t *testing.T) {
+	t.Parallel()
+
+	querier := &trackingCruxQuerier{}
+	_, _, err := queryCruxCurrent(context.Background(), querier, cruxDataInput{
+		Target:  "https://example.test",
+		Metrics: []string{"largest_contentfull_paint"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "largest_contentful_paint") {
+		t.Errorf("err = %v, want suggestion for largest_contentful_paint", err)
+	}
+	if calls := querier.calls.Load(); calls != 0 {
+		t.Errorf("API calls = %d, want 0", calls)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+)
+
+type limitedCruxQuerier struct {
+	querier     cruxQuerier
+	slots       chan struct{}
+	minInterval time.Duration
+
+	mutex     sync.Mutex
+	nextStart time.Time
+}
+
+func newLimitedCruxQuerier(
+	querier cruxQuerier,
+	maxConcurrency int,
+	minInterval time.Duration,
+) *limitedCruxQuerier {
+	if maxConcurrency < 1 {
+		panic("maxConcurrency must be positive")
+	}
+	return &limitedCruxQuerier{
+		querier:     querier,
+		slots:       make(chan struct{}, maxConcurrency),
+		minInterval: minInterval,
+	}
+}
+
+// QueryCurrent runs the origin fallback here rather than in the wrapped
+// querier, so the URL and origin requests each wait for their own slot and
+// interval.
+func (q *limitedCruxQuerier) QueryCurrent(
+	ctx context.Context,
+	request crux.QueryRequest,
+) (*crux.Result, error) {
+	return crux.QueryWithOriginFallback(ctx, request, func(
+		ctx context.Context,
+		request crux.QueryRequest,
+	) (*crux.Result, error) {
+		release, err := q.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return q.querier.QueryCurrent(ctx, request)
+	})
+}
+
+// QueryHistory limits the URL and origin requests of a fallback separately,
+// like QueryCurrent.
+func (q *limitedCruxQuerier) QueryHistory(
+	ctx context.Context,
+	request crux.QueryRequest,
+) (*crux.HistoryResult, error) {
+	return crux.QueryWithOriginFallback(ctx, request, func(
+		ctx context.Context,
+		request crux.QueryRequest,
+	) (*crux.HistoryResult, error) {
+		release, err := q.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return q.querier.QueryHistory(ctx, request)
+	})
+}
+
+// acquire waits for a concurrency slot and then for the next request start
+// permitted by the minimum interval.
+func (q *limitedCruxQuerier) acquire(ctx context.Context) (func(), error) {
+	select {
+	case q.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := func() { <-q.slots }
+
+	q.mutex.Lock()
+	now := time.Now()
+	start := q.nextStart
+	if start.Before(now) {
+		start = now
+	}
+	q.nextStart = start.Add(q.minInterval)
+	q.mutex.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return release, nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return release, nil
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
+}
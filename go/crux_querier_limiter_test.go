@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/apihttp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+)
+
+type trackingCruxQuerier struct {
+	fakeCruxQuerier
+	active    atomic.Int32
+	maxActive atomic.Int32
+	calls     atomic.Int32
+}
+
+func (q *trackingCruxQuerier) QueryCurrent(
+	ctx context.Context,
+	request crux.QueryRequest,
+) (*crux.Result, error) {
+	q.calls.Add(1)
+	active := q.active.Add(1)
+	for {
+		currentMax := q.maxActive.Load()
+		if active <= currentMax || q.maxActive.CompareAndSwap(currentMax, active) {
+			break
+		}
+	}
+	defer q.active.Add(-1)
+	time.Sleep(10 * time.Millisecond)
+	return q.fakeCruxQuerier.QueryCurrent(ctx, request)
+}
+
+func TestLimitedCruxQuerier_BoundsConcurrencyAndSpacesRequests(t *testing.T) {
+	t.Parallel()
+
+	querier := &trackingCruxQuerier{}
+	limited := newLimitedCruxQuerier(querier, 2, 5*time.Millisecond)
+	request, err := crux.NewQueryRequest("https://example.test", "origin", "", nil, 0)
+	if err != nil {
+		t.Fatalf("NewQueryRequest: %v", err)
+	}
+
+	started := time.Now()
+	var waitGroup sync.WaitGroup
+	for range 6 {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			if _, err := limited.QueryCurrent(context.Background(), request); err != nil {
+				t.Errorf("QueryCurrent: %v", err)
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	if max := querier.maxActive.Load(); max > 2 {
+		t.Errorf("max concurrency = %d, want at most 2", max)
+	}
+	if elapsed := time.Since(started); elapsed < 25*time.Millisecond {
+		t.Errorf("elapsed = %v, want requests spaced by the minimum interval", elapsed)
+	}
+}
+
+// originOnlyCruxQuerier has no URL-level records and records when each
+// request started.
+type originOnlyCruxQuerier struct {
+	fakeCruxQuerier
+	mutex  sync.Mutex
+	starts []time.Time
+}
+
+func (q *originOnlyCruxQuerier) QueryCurrent(
+	ctx context.Context,
+	request crux.QueryRequest,
+) (*crux.Result, error) {
+	q.mutex.Lock()
+	q.starts = append(q.starts, time.Now())
+	q.mutex.Unlock()
+	if request.OriginFallback {
+		return nil, fmt.Errorf("wrapped querier must not run the origin fallback")
+	}
+	if request.TargetType == "url" {
+		return nil, &apihttp.StatusError{
+			Service:    "CrUX API",
+			StatusCode: http.StatusNotFound,
+			Status:     "NOT_FOUND",
+		}
+	}
+	return q.fakeCruxQuerier.QueryCurrent(ctx, request)
+}
+
+func TestLimitedCruxQuerier_SpacesOriginFallbackRequests(t *testing.T) {
+	t.Parallel()
+
+	querier := &originOnlyCruxQuerier{}
+	limited := newLimitedCruxQuerier(querier, 1, 20*time.Millisecond)
+	request, err := crux.NewQueryRequest("https://example.test/quiet", "url", "", nil, 0)
+	if err != nil {
+		t.Fatalf("NewQueryRequest: %v", err)
+	}
+	request.OriginFallback = true
+
+	result, err := limited.QueryCurrent(context.Background(), request)
+	if err != nil {
+		t.Fatalf("QueryCurrent: %v", err)
+	}
+	if !result.OriginFallback || result.Target != "https://example.test" ||
+		result.RequestedTarget != "https://example.test/quiet" {
+		t.Errorf("result = %+v, want origin data marked as a fallback", result)
+	}
+	if len(querier.starts) != 2 {
+		t.Fatalf("requests = %d, want URL and origin", len(querier.starts))
+	}
+	if gap := querier.starts[1].Sub(querier.starts[0]); gap < 20*time.Millisecond {
+		t.Errorf("origin request started %v after the URL request, want the minimum interval", gap)
+	}
+}
+
+func TestLimitedCruxQuerier_RespectsCancellationWhileWaiting(t *testing.T) {
+	t.Parallel()
+
+	limited := newLimitedCruxQuerier(&trackingCruxQuerier{}, 1, 0)
+	limited.slots <- struct{}{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request, err := crux.NewQueryRequest("https://example.test", "origin", "", nil, 0)
+	if err != nil {
+		t.Fatalf("NewQueryRequest: %v", err)
+	}
+
+	if _, err := limited.QueryHistory(ctx, request); err == nil {
+		t.Fatal("QueryHistory returned nil error")
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+)
+
+// cruxFailure is the structured error body returned by the CrUX tools.
+type cruxFailure struct {
+	Target         string              `json:"target"`
+	TargetType     string              `json:"targetType"`
+	FormFactor     string              `json:"formFactor"`
+	Code           string              `json:"code"`
+	Message        string              `json:"message"`
+	Reason         string              `json:"reason,omitempty"`
+	Retryable      bool                `json:"retryable"`
+	Hint           string              `json:"hint,omitempty"`
+	OriginFallback *cruxOriginFallback `json:"originFallback,omitempty"`
+}
+
+// cruxOriginFallback identifies the origin-level query to try when URL-level
+// data is unavailable.
+type cruxOriginFallback struct {
+	Target     string `json:"target"`
+	TargetType string `json:"targetType"`
+}
+
+// queryCruxCurrent runs one current CrUX query and returns a JSON tool result.
+func queryCruxCurrent(
+	ctx context.Context,
+	cruxClient cruxQuerier,
+	input cruxDataInput,
+) (*mcp.CallToolResult, any, error) {
+	request, err := crux.NewQueryRequest(
+		input.Target,
+		input.TargetType,
+		input.FormFactor,
+		input.Metrics,
+		0,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	percentiles, err := crux.NormalizePercentiles(input.Percentiles)
+	if err != nil {
+		return nil, nil, err
+	}
+	request.OriginFallback = input.Fallback
+	result, err := cruxClient.QueryCurrent(ctx, request)
+	if err != nil {
+		return cruxFailureResult(ctx, request, err)
+	}
+	result.EstimatePercentiles(percentiles)
+	return jsonToolResult(result)
+}
+
+// queryCruxHistory runs one historical CrUX query and returns a JSON tool result.
+func queryCruxHistory(
+	ctx context.Context,
+	cruxClient cruxQuerier,
+	input cruxHistoryInput,
+) (*mcp.CallToolResult, any, error) {
+	request, err := crux.NewQueryRequest(
+		input.Target,
+		input.TargetType,
+		input.FormFactor,
+		input.Metrics,
+		input.CollectionPeriodCount,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	percentiles, err := crux.NormalizePercentiles(input.Percentiles)
+	if err != nil {
+		return nil, nil, err
+	}
+	request.OriginFallback = input.Fallback
+	result, err := cruxClient.QueryHistory(ctx, request)
+	if err != nil {
+		return cruxFailureResult(ctx, request, err)
+	}
+	result.EstimatePercentiles(percentiles)
+	return jsonToolResult(result)
+}
+
+// cruxFailureResult reports an upstream CrUX failure as a tool error so the
+// assistant can distinguish missing data from protocol failures.
+func cruxFailureResult(
+	ctx context.Context,
+	request crux.QueryRequest,
+	err error,
+) (*mcp.CallToolResult, any, error) {
+	if ctx.Err() != nil {
+		return nil, nil, ctx.Err()
+	}
+	slog.Warn(
+		"CrUX query failed",
+		"target",
+		request.Target,
+		"formFactor",
+		request.FormFactor,
+		"err",
+		err,
+	)
+	return errorToolResult(classifyCruxFailure(request, err))
+}
+
+func classifyCruxFailure(request crux.QueryRequest, err error) cruxFailure {
+	failure := describeAPIFailure(err)
+	result := cruxFailure{
+		Target:     request.Target,
+		TargetType: request.TargetType,
+		FormFactor: request.FormFactor,
+		Code:       failure.Code,
+		Message:    failure.Message,
+		Reason:     failure.Reason,
+		Retryable:  failure.Retryable,
+		Hint:       failure.Hint,
+	}
+	if failure.Code != "no_field_data" {
+		return result
+	}
+	origin, ok := request.OriginRequest()
+	if ok && request.OriginFallback {
+		result.Hint = fmt.Sprintf(
+			"CrUX has no record for this page or its origin %s either. "+
+				"Try form_factor all or a higher-traffic target.",
+			origin.Target,
+		)
+		return result
+	}
+	if ok {
+		result.OriginFallback = &cruxOriginFallback{
+			Target:     origin.Target,
+			TargetType: origin.TargetType,
+		}
+		result.Hint = fmt.Sprintf(
+			"CrUX has no URL-level record for this page. Query the origin %s with target_type origin.",
+			origin.Target,
+		)
+	}
+	return result
+}
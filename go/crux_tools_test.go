@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/apihttp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+)
+
+type failingCruxQuerier struct {
+	err error
+}
+
+func (q failingCruxQuerier) QueryCurrent(
+	context.Context,
+	crux.QueryRequest,
+) (*crux.Result, error) {
+	return nil, q.err
+}
+
+func (q failingCruxQuerier) QueryHistory(
+	context.Context,
+	crux.QueryRequest,
+) (*crux.HistoryResult, error) {
+	return nil, q.err
+}
+
+func TestQueryCruxCurrent_NotFound_ReturnsStructuredToolError(t *testing.T) {
+	t.Parallel()
+
+	querier := failingCruxQuerier{err: &apihttp.StatusError{
+		Service:    "CrUX API",
+		StatusCode: http.StatusNotFound,
+		Status:     "NOT_FOUND",
+		Message:    "chrome ux report data not found",
+	}}
+	result, _, err := queryCruxCurrent(context.Background(), querier, cruxDataInput{
+		Target:     "https://example.test/quiet-page",
+		FormFactor: "phone",
+	})
+	if err != nil {
+		t.Fatalf("queryCruxCurrent returned protocol error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("result must be marked as a tool error")
+	}
+
+	failure := decodeToolText[cruxFailure](t, result)
+	if failure.Code != "no_field_data" || failure.Retryable {
+		t.Errorf("failure = %+v, want non-retryable no_field_data", failure)
+	}
+	if failure.OriginFallback == nil ||
+		failure.OriginFallback.Target != "https://example.test" ||
+		failure.OriginFallback.TargetType != "origin" {
+		t.Errorf("origin fallback = %+v, want example.test origin", failure.OriginFallback)
+	}
+}
+
+func TestQueryCruxCurrent_OriginFallbackNotFound_HintsOriginHasNoRecord(t *testing.T) {
+	t.Parallel()
+
+	querier := failingCruxQuerier{err: &apihttp.StatusError{
+		Service:    "CrUX API",
+		StatusCode: http.StatusNotFound,
+		Status:     "NOT_FOUND",
+		Message:    "chrome ux report data not found",
+	}}
+	result, _, err := queryCruxCurrent(context.Background(), querier, cruxDataInput{
+		Target:   "https://example.test/quiet-page",
+		Fallback: true,
+	})
+	if err != nil {
+		t.Fatalf("queryCruxCurrent returned protocol error: %v", err)
+	}
+
+	failure := decodeToolText[cruxFailure](t, result)
+	if failure.Code != "no_field_data" || failure.OriginFallback != nil {
+		t.Errorf("failure = %+v, want no_field_data without an origin fallback", failure)
+	}
+	if strings.Contains(failure.Hint, "Try the origin") ||
+		!strings.Contains(failure.Hint, "origin https://example.test either") ||
+		!strings.Contains(failure.Hint, "form_factor all") {
+		t.Errorf("hint = %q, want origin has no record and form_factor all", failure.Hint)
+	}
+}
+
+func TestQueryCruxHistory_ServiceDisabled_ReturnsStructuredToolError(t *testing.T) {
+	t.Parallel()
+
+	querier := failingCruxQuerier{err: &apihttp.StatusError{
+		Service:    "CrUX API",
+		StatusCode: http.StatusForbidden,
+		Status:     "PERMISSION_DENIED",
+		Message:    "Chrome UX Report API has not been used in project 123.",
+		Reason:     "SERVICE_DISABLED",
+		Metadata:   map[string]string{"service": "chromeuxreport.googleapis.com"},
+	}}
+	result, _, err := queryCruxHistory(context.Background(), querier, cruxHistoryInput{
+		Target:     "https://example.test",
+		TargetType: "origin",
+	})
+	if err != nil {
+		t.Fatalf("queryCruxHistory returned protocol error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("result must be marked as a tool error")
+	}
+
+	failure := decodeToolText[cruxFailure](t, result)
+	if failure.Code != "api_not_enabled" || failure.Reason != "SERVICE_DISABLED" {
+		t.Errorf("failure = %+v, want api_not_enabled", failure)
+	}
+	if failure.OriginFallback != nil {
+		t.Errorf("origin targets must not offer a fallback: %+v", failure.OriginFallback)
+	}
+}
+
+func decodeToolText[T any](t *testing.T, result *mcp.CallToolResult) T {
+	t.Helper()
+
+	var value T
+	text, ok := result.Content[0].(*mcp.TextContent)
+	if !ok {
+		t.Fatalf("content type = %T, want *mcp.TextContent", result.Content[0])
+	}
+	if err := json.Unmarshal([]byte(text.Text), &value); err != nil {
+		t.Fatalf("unmarshal tool result: %v", err)
+	}
+	return value
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+)
+
+const maxTrendWindows = 5
+
+// cruxTrendInput is the input schema for the analyze_crux_trend tool.
+type cruxTrendInput struct {
+	Target                string   `json:"target"`
+	TargetType            string   `json:"target_type,omitempty"`
+	FormFactor            string   `json:"form_factor,omitempty"`
+	Metrics               []string `json:"metrics,omitempty"`
+	CollectionPeriodCount int      `json:"collection_period_count,omitempty"`
+	Windows               []int    `json:"windows,omitempty"`
+	Fallback              bool     `json:"fallback,omitempty"`
+}
+
+// analyzeCruxTrend queries CrUX history and returns per-metric trend statistics.
+func analyzeCruxTrend(
+	ctx context.Context,
+	cruxClient cruxQuerier,
+	input cruxTrendInput,
+) (*mcp.CallToolResult, any, error) {
+	if len(input.Windows) > maxTrendWindows {
+		return nil, nil, fmt.Errorf("at most %d windows may be requested", maxTrendWindows)
+	}
+	for _, window := range input.Windows {
+		if window < 1 || window > 39 {
+			return nil, nil, fmt.Errorf("windows must be between 1 and 39 periods")
+		}
+	}
+	request, err := crux.NewQueryRequest(
+		input.Target,
+		input.TargetType,
+		input.FormFactor,
+		input.Metrics,
+		input.CollectionPeriodCount,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	request.OriginFallback = input.Fallback
+
+	history, err := cruxClient.QueryHistory(ctx, request)
+	if err != nil {
+		return cruxFailureResult(ctx, request, err)
+	}
+	return jsonToolResult(crux.AnalyzeTrend(history, input.Windows))
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/apihttp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+)
+
+// improvingHistoryQuerier returns four periods of steadily falling LCP.
+type improvingHistoryQuerier struct {
+	fakeCruxQuerier
+	calls atomic.Int32
+}
+
+func (q *improvingHistoryQuerier) QueryHistory(
+	_ context.Context,
+	request crux.QueryRequest,
+) (*crux.HistoryResult, error) {
+	q.calls.Add(1)
+	p75 := []*float64{new(4000.0), new(3500.0), new(3000.0), new(2500.0)}
+	periods := make([]crux.CollectionPeriod, len(p75))
+	for index := range periods {
+		periods[index].LastDate = crux.Date{Year: 2026, Month: 1, Day: 7*index + 1}
+	}
+	return &crux.HistoryResult{
+		Target:            request.Target,
+		TargetType:        request.TargetType,
+		CollectionPeriods: periods,
+		Metrics: map[string]crux.HistoryMetric{
+			"largest_contentful_paint": {P75: p75},
+		},
+	}, nil
+}
+
+func TestAnalyzeCruxTrend_ReturnsTrendPerMetric(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := analyzeCruxTrend(context.Background(), &improvingHistoryQuerier{}, cruxTrendInput{
+		Target:  "https://example.test",
+		Metrics: []string{"lcp"},
+		Windows: []int{2},
+	})
+	if err != nil {
+		t.Fatalf("analyzeCruxTrend: %v", err)
+	}
+
+	analysis := decodeToolText[crux.TrendAnalysis](t, result)
+	if analysis.Target != "https://example.test" || len(analysis.CollectionPeriods) != 4 {
+		t.Errorf("analysis = %+v", analysis)
+	}
+	lcp, ok := analysis.Metrics["largest_contentful_paint"]
+	if !ok {
+		t.Fatalf("metrics = %+v, want largest_contentful_paint", analysis.Metrics)
+	}
+	if lcp.AvailablePeriods != 4 || lcp.Direction != "improving" || lcp.Latest == nil || *lcp.Latest != 2500 {
+		t.Errorf("lcp trend = %+v, want improving to 2500", lcp)
+	}
+	if len(lcp.Changes) != 1 || lcp.Changes[0].Periods != 2 ||
+		lcp.Changes[0].AbsoluteChange == nil || *lcp.Changes[0].AbsoluteChange != -1000 {
+		t.Errorf("changes = %+v, want one 2-period window of -1000", lcp.Changes)
+	}
+}
+
+func TestAnalyzeCruxTrend_RejectsInvalidInputBeforeCallingAPI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input cruxTrendInput
+	}{
+		{name: "too many windows", input: cruxTrendInput{Windows: []int{1, 2, 3, 4, 5, 6}}},
+		{name: "window below one", input: cruxTrendInput{Windows: []int{0}}},
+		{name: "window above 39", input: cruxTrendInput{Windows: []int{40}}},
+		{name: "unknown metric", input: cruxTrendInput{Metrics: []string{"largest_paint"}}},
+		{name: "relative target", input: cruxTrendInput{Target: "example.test"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			querier := &improvingHistoryQuerier{}
+			input := test.input
+			if input.Target == "" {
+				input.Target = "https://example.test"
+			}
+			if _, _, err := analyzeCruxTrend(context.Background(), querier, input); err == nil {
+				t.Errorf("analyzeCruxTrend(%+v) returned nil error", input)
+			}
+			if calls := querier.calls.Load(); calls != 0 {
+				t.Errorf("API calls = %d, want 0", calls)
+			}
+		})
+	}
+}
+
+func TestAnalyzeCruxTrend_NotFound_ReturnsStructuredToolError(t *testing.T) {
+	t.Parallel()
+
+	querier := failingCruxQuerier{err: &apihttp.StatusError{
+		Service:    "CrUX API",
+		StatusCode: http.StatusNotFound,
+		Status:     "NOT_FOUND",
+		Message:    "chrome ux report data not found",
+	}}
+	result, _, err := analyzeCruxTrend(context.Background(), querier, cruxTrendInput{
+		Target: "https://example.test/quiet-page",
+	})
+	if err != nil {
+		t.Fatalf("analyzeCruxTrend returned protocol error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("result must be marked as a tool error")
+	}
+
+	failure := decodeToolText[cruxFailure](t, result)
+	if failure.Code != "no_field_data" || failure.Target != "https://example.test/quiet-page" ||
+		failure.OriginFallback == nil {
+		t.Errorf("failure = %+v, want no_field_data with an origin fallback", failure)
+	}
+}
@@ -3,6 +3,7 @@ package apihttp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -33,15 +34,82 @@ type StatusError struct {
 	StatusCode int
 	// BodySnippet contains a bounded response excerpt.
 	BodySnippet string
+	// Status is the canonical Google error status, such as PERMISSION_DENIED.
+	Status string
+	// Message is the human-readable message from the Google error envelope.
+	Message string
+	// Reason is the ErrorInfo or legacy error reason, such as SERVICE_DISABLED.
+	Reason string
+	// Domain is the logical error domain reported with Reason.
+	Domain string
+	// Metadata contains ErrorInfo details such as the service and activation URL.
+	Metadata map[string]string
+}
+
+// NewStatusError decodes a Google JSON error envelope from a non-success response.
+// Bodies that are not Google error envelopes retain only the bounded snippet.
+func NewStatusError(service string, response *Response, maxSnippetLength int) *StatusError {
+	statusError := &StatusError{
+		Service:     service,
+		StatusCode:  response.StatusCode,
+		BodySnippet: truncate(string(response.Body), maxSnippetLength),
+	}
+
+	var envelope rawErrorEnvelope
+	if err := json.Unmarshal(response.Body, &envelope); err != nil || envelope.Error == nil {
+		return statusError
+	}
+	statusError.Status = envelope.Error.Status
+	statusError.Message = envelope.Error.Message
+	for _, detail := range envelope.Error.Details {
+		if detail.Type != errorInfoType || detail.Reason == "" {
+			continue
+		}
+		statusError.Reason = detail.Reason
+		statusError.Domain = detail.Domain
+		statusError.Metadata = detail.Metadata
+		break
+	}
+	if statusError.Reason == "" {
+		for _, legacy := range envelope.Error.Errors {
+			if legacy.Reason == "" {
+				continue
+			}
+			statusError.Reason = legacy.Reason
+			statusError.Domain = legacy.Domain
+			break
+		}
+	}
+	return statusError
 }
 
 // Error returns the formatted upstream status failure.
 func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf(
+			"%s returned HTTP %d: %s",
+			e.Service,
+			e.StatusCode,
+			e.BodySnippet,
+		)
+	}
+
+	qualifier := e.Status
+	if e.Reason != "" {
+		if qualifier != "" {
+			qualifier += "/"
+		}
+		qualifier += e.Reason
+	}
+	if qualifier == "" {
+		return fmt.Sprintf("%s returned HTTP %d: %s", e.Service, e.StatusCode, e.Message)
+	}
 	return fmt.Sprintf(
-		"%s returned HTTP %d: %s",
+		"%s returned HTTP %d (%s): %s",
 		e.Service,
 		e.StatusCode,
-		e.BodySnippet,
+		qualifier,
+		e.Message,
 	)
 }
 
@@ -50,6 +118,31 @@ func (e *StatusError) Retryable() bool {
 	return IsRetryableStatus(e.StatusCode)
 }
 
+const errorInfoType = "type.googleapis.com/google.rpc.ErrorInfo"
+
+type rawErrorEnvelope struct {
+	Error *rawError `json:"error"`
+}
+
+type rawError struct {
+	Message string           `json:"message"`
+	Status  string           `json:"status"`
+	Details []rawErrorDetail `json:"details"`
+	Errors  []rawLegacyError `json:"errors"`
+}
+
+type rawErrorDetail struct {
+	Type     string            `json:"@type"`
+	Reason   string            `json:"reason"`
+	Domain   string            `json:"domain"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+type rawLegacyError struct {
+	Reason string `json:"reason"`
+	Domain string `json:"domain"`
+}
+
 // Do sends a request and retries transient transport and HTTP failures.
 func Do(
 	ctx context.Context,
@@ -110,6 +203,13 @@ func IsRetryableStatus(statusCode int) bool {
 	return statusCode == http.StatusTooManyRequests || statusCode >= 500
 }
 
+func truncate(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+	return value[:maxLength] + "..."
+}
+
 func retryDelay(retryAfter string, attempt int) time.Duration {
 	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
 		return time.Duration(seconds) * time.Second
@@ -54,3 +54,76 @@ func TestStatusError_Retryable(t *testing.T) {
 		t.Error("400 must not be retryable")
 	}
 }
+
+func TestNewStatusError_DecodesGoogleErrorEnvelope(t *testing.T) {
+	t.Parallel()
+
+	statusError := NewStatusError("CrUX API", &Response{
+		StatusCode: http.StatusForbidden,
+		Body: []byte(`{
+			"error": {
+				"code": 403,
+				"message": "Chrome UX Report API has not been used in project 123.",
+				"status": "PERMISSION_DENIED",
+				"details": [
+					{"@type": "type.googleapis.com/google.rpc.Help", "links": []},
+					{
+						"@type": "type.googleapis.com/google.rpc.ErrorInfo",
+						"reason": "SERVICE_DISABLED",
+						"domain": "googleapis.com",
+						"metadata": {
+							"service": "chromeuxreport.googleapis.com",
+							"activationUrl": "https://console.developers.google.com/apis/api/chromeuxreport.googleapis.com/overview?project=123"
+						}
+					}
+				]
+			}
+		}`),
+	}, 500)
+
+	if statusError.Status != "PERMISSION_DENIED" {
+		t.Errorf("status = %q, want PERMISSION_DENIED", statusError.Status)
+	}
+	if statusError.Reason != "SERVICE_DISABLED" || statusError.Domain != "googleapis.com" {
+		t.Errorf("reason = %q (%s), want SERVICE_DISABLED", statusError.Reason, statusError.Domain)
+	}
+	if got := statusError.Metadata["service"]; got != "chromeuxreport.googleapis.com" {
+		t.Errorf("metadata service = %q, want CrUX service", got)
+	}
+	if statusError.Retryable() {
+		t.Error("403 must not be retryable")
+	}
+}
+
+func TestNewStatusError_NonEnvelopeBody_KeepsSnippet(t *testing.T) {
+	t.Parallel()
+
+	statusError := NewStatusError("PSI API", &Response{
+		StatusCode: http.StatusBadGateway,
+		Body:       []byte("<html>bad gateway</html>"),
+	}, 10)
+
+	if statusError.BodySnippet != "<html>bad ..." {
+		t.Errorf("snippet = %q, want truncated body", statusError.BodySnippet)
+	}
+	if statusError.Status != "" || statusError.Reason != "" {
+		t.Errorf("unexpected decoded fields: %+v", statusError)
+	}
+}
+
+func TestNewStatusError_LegacyErrors_ProvideReason(t *testing.T) {
+	t.Parallel()
+
+	statusError := NewStatusError("PSI API", &Response{
+		StatusCode: http.StatusInternalServerError,
+		Body: []byte(`{"error":{"code":500,"message":"Lighthouse returned error: FAILED_DOCUMENT_REQUEST.",` +
+			`"errors":[{"message":"x","domain":"lighthouse","reason":"lighthouseUserError"}]}}`),
+	}, 300)
+
+	if statusError.Reason != "lighthouseUserError" || statusError.Domain != "lighthouse" {
+		t.Errorf("legacy reason = %q (%s)", statusError.Reason, statusError.Domain)
+	}
+	if statusError.Message != "Lighthouse returned error: FAILED_DOCUMENT_REQUEST." {
+		t.Errorf("message = %q", statusError.Message)
+	}
+}
@@ -0,0 +1,116 @@
+package crux
+
+import "github.com/ncosentino/google-psi-mcp/go/internal/webvitals"
+
+// webVitalNames maps CrUX metric names to shared web vitals short names.
+var webVitalNames = map[string]string{
+	"largest_contentful_paint":        "lcp",
+	"interaction_to_next_paint":       "inp",
+	"cumulative_layout_shift":         "cls",
+	"first_contentful_paint":          "fcp",
+	"experimental_time_to_first_byte": "ttfb",
+}
+
+// roundTripTimeThreshold buckets round_trip_time, which is not a web vital and
+// has no Google rating. Its boundaries follow the CrUX histogram bins.
+var roundTripTimeThreshold = webvitals.Threshold{Good: 75, Poor: 275, Unit: "ms"}
+
+// metricThreshold returns the rating boundaries for a CrUX metric name.
+func metricThreshold(name string) (webvitals.Threshold, bool) {
+	if name == "round_trip_time" {
+		return roundTripTimeThreshold, true
+	}
+	return webvitals.ThresholdFor(webVitalNames[name])
+}
+
+func assessCurrent(metrics map[string]Metric) *webvitals.Assessment {
+	p75s := make(map[string]*float64, len(webvitals.CoreWebVitals))
+	for name, metric := range metrics {
+		if shortName, ok := webVitalNames[name]; ok {
+			p75s[shortName] = metric.P75
+		}
+	}
+	return webvitals.Assess(p75s)
+}
+
+func assessHistory(metrics map[string]HistoryMetric, periods int) []*webvitals.Assessment {
+	if periods == 0 {
+		return nil
+	}
+	assessments := make([]*webvitals.Assessment, periods)
+	for index := range assessments {
+		p75s := make(map[string]*float64, len(webvitals.CoreWebVitals))
+		for name, metric := range metrics {
+			shortName, ok := webVitalNames[name]
+			if ok && index < len(metric.P75) {
+				p75s[shortName] = metric.P75[index]
+			}
+		}
+		assessments[index] = webvitals.Assess(p75s)
+	}
+	return assessments
+}
+
+// rateCurrent derives the p75 rating and rating-range proportions of a known metric.
+func rateCurrent(name string, metric *Metric) {
+	threshold, ok := metricThreshold(name)
+	if !ok {
+		return
+	}
+	if metric.P75 != nil {
+		metric.Rating = threshold.Rate(*metric.P75)
+	}
+	if len(metric.Histogram) == 0 {
+		return
+	}
+	proportions := &RatingProportions{}
+	for _, bin := range metric.Histogram {
+		proportions.add(threshold, bin.Start, bin.End, bin.Density)
+	}
+	metric.Proportions = proportions
+}
+
+// rateHistory derives per-period ratings and rating-range proportions of a known metric.
+func rateHistory(name string, metric *HistoryMetric, periods int) {
+	threshold, ok := metricThreshold(name)
+	if !ok || periods == 0 {
+		return
+	}
+	if len(metric.P75) > 0 {
+		metric.Ratings = make([]*string, periods)
+		for index := range metric.Ratings {
+			if index < len(metric.P75) && metric.P75[index] != nil {
+				rating := threshold.Rate(*metric.P75[index])
+				metric.Ratings[index] = &rating
+			}
+		}
+	}
+	if len(metric.Histogram) == 0 {
+		return
+	}
+	metric.Proportions = make([]*RatingProportions, periods)
+	for index := range metric.Proportions {
+		proportions := &RatingProportions{}
+		for _, bin := range metric.Histogram {
+			if index >= len(bin.Densities) || bin.Densities[index] == nil {
+				proportions = nil
+				break
+			}
+			proportions.add(threshold, bin.Start, bin.End, *bin.Densities[index])
+		}
+		metric.Proportions[index] = proportions
+	}
+}
+
+// add assigns a histogram bin's density to the rating range containing it.
+// CrUX bins for known metrics align with the rating boundaries.
+func (p *RatingProportions) add(threshold webvitals.Threshold, start, end *float64, density float64) {
+	switch {
+	case end != nil && *end <= threshold.Good:
+		p.Good += density
+	case start != nil && *start >= threshold.Poor:
+		p.Poor += density
+	default:
+		p.NeedsImprovement += density
+	}
+}
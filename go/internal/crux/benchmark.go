@@ -0,0 +1,194 @@
+package crux
+
+import (
+	"sort"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/webvitals"
+)
+
+// CoreWebVitals contains the CrUX metric names assessed as Core Web Vitals.
+var CoreWebVitals = []string{
+	"largest_contentful_paint",
+	"interaction_to_next_paint",
+	"cumulative_layout_shift",
+}
+
+// trendTolerance is the relative p75 change treated as stable.
+const trendTolerance = 0.05
+
+// BenchmarkInput contains the CrUX data collected for one benchmarked origin.
+type BenchmarkInput struct {
+	// Origin is the benchmarked origin.
+	Origin string
+	// Primary reports whether the origin is the caller's own origin.
+	Primary bool
+	// Current is the current CrUX record, or nil when it is unavailable.
+	Current *Result
+	// History is the historical CrUX record, or nil when it is unavailable.
+	History *HistoryResult
+}
+
+// Benchmark ranks origins by their Core Web Vitals.
+type Benchmark struct {
+	// Origins contains each origin's overall Core Web Vitals verdict, best first.
+	Origins []OriginBenchmark `json:"origins"`
+	// Metrics contains one ranked table per Core Web Vital, keyed by CrUX metric name.
+	Metrics map[string][]MetricBenchmark `json:"metrics"`
+}
+
+// OriginBenchmark summarizes one origin's Core Web Vitals assessment.
+type OriginBenchmark struct {
+	// Rank is the one-based position among the benchmarked origins.
+	Rank int `json:"rank"`
+	// Origin is the benchmarked origin.
+	Origin string `json:"origin"`
+	// Primary reports whether the origin is the caller's own origin.
+	Primary bool `json:"primary"`
+	// PassesCoreWebVitals is the assessment verdict, or null without LCP and CLS data.
+	PassesCoreWebVitals *bool `json:"passesCoreWebVitals"`
+	// GoodMetrics counts the Core Web Vitals whose p75 is good.
+	GoodMetrics int `json:"goodMetrics"`
+}
+
+// MetricBenchmark contains one origin's entry in a ranked metric table.
+type MetricBenchmark struct {
+	// Rank is the one-based position by p75; origins without data rank last.
+	Rank int `json:"rank"`
+	// Origin is the benchmarked origin.
+	Origin string `json:"origin"`
+	// Primary reports whether the origin is the caller's own origin.
+	Primary bool `json:"primary"`
+	// P75 is the current 75th-percentile value.
+	P75 *float64 `json:"p75"`
+	// GoodProportion is the share of experiences in the good histogram range.
+	GoodProportion *float64 `json:"goodProportion"`
+	// Passes reports whether the p75 meets the good threshold.
+	Passes *bool `json:"passes"`
+	// Trend is improving, regressing, stable, or insufficient_data.
+	Trend string `json:"trend"`
+	// TrendChange is the relative p75 change across the trend window.
+	TrendChange *float64 `json:"trendChange,omitempty"`
+}
+
+// NewBenchmark ranks origins per Core Web Vital and derives trends from the
+// last trendPeriods collection periods of each history.
+func NewBenchmark(inputs []BenchmarkInput, trendPeriods int) *Benchmark {
+	benchmark := &Benchmark{
+		Origins: make([]OriginBenchmark, 0, len(inputs)),
+		Metrics: make(map[string][]MetricBenchmark, len(CoreWebVitals)),
+	}
+
+	for _, metricName := range CoreWebVitals {
+		entries := make([]MetricBenchmark, 0, len(inputs))
+		for _, input := range inputs {
+			entry := MetricBenchmark{
+				Origin:  input.Origin,
+				Primary: input.Primary,
+				Trend:   "insufficient_data",
+			}
+			if input.Current != nil {
+				if metric, ok := input.Current.Metrics[metricName]; ok && metric.P75 != nil {
+					entry.P75 = cloneNumber(metric.P75)
+					rateCurrent(metricName, &metric)
+					passes := metric.Rating == webvitals.RatingGood
+					entry.Passes = &passes
+					if metric.Proportions != nil {
+						entry.GoodProportion = &metric.Proportions.Good
+					}
+				}
+			}
+			if input.History != nil {
+				entry.Trend, entry.TrendChange = p75Trend(
+					input.History.Metrics[metricName].P75,
+					trendPeriods,
+				)
+			}
+			entries = append(entries, entry)
+		}
+
+		sort.SliceStable(entries, func(i, j int) bool {
+			left, right := entries[i].P75, entries[j].P75
+			if left == nil || right == nil {
+				return left != nil
+			}
+			return *left < *right
+		})
+		for index := range entries {
+			entries[index].Rank = index + 1
+		}
+		benchmark.Metrics[metricName] = entries
+	}
+
+	for _, input := range inputs {
+		summary := OriginBenchmark{
+			Origin:  input.Origin,
+			Primary: input.Primary,
+		}
+		if input.Current != nil {
+			if assessment := assessCurrent(input.Current.Metrics); assessment != nil {
+				summary.PassesCoreWebVitals = assessment.Passes
+				for _, metric := range assessment.Metrics {
+					if metric.Rating == webvitals.RatingGood {
+						summary.GoodMetrics++
+					}
+				}
+			}
+		}
+		benchmark.Origins = append(benchmark.Origins, summary)
+	}
+	sort.SliceStable(benchmark.Origins, func(i, j int) bool {
+		left, right := benchmark.Origins[i], benchmark.Origins[j]
+		if passRank(left.PassesCoreWebVitals) != passRank(right.PassesCoreWebVitals) {
+			return passRank(left.PassesCoreWebVitals) < passRank(right.PassesCoreWebVitals)
+		}
+		return left.GoodMetrics > right.GoodMetrics
+	})
+	for index := range benchmark.Origins {
+		benchmark.Origins[index].Rank = index + 1
+	}
+	return benchmark
+}
+
+func passRank(passes *bool) int {
+	switch {
+	case passes == nil:
+		return 2
+	case *passes:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// p75Trend compares the first and last available p75 values in the most
+// recent periods. Lower values are better for every Core Web Vital.
+func p75Trend(series []*float64, periods int) (string, *float64) {
+	if periods > 0 && len(series) > periods {
+		series = series[len(series)-periods:]
+	}
+	var first, last *float64
+	available := 0
+	for _, value := range series {
+		if value == nil {
+			continue
+		}
+		if first == nil {
+			first = value
+		}
+		last = value
+		available++
+	}
+	if available < 2 || *first == 0 {
+		return "insufficient_data", nil
+	}
+
+	change := (*last - *first) / *first
+	switch {
+	case change <= -trendTolerance:
+		return "improving", &change
+	case change >= trendTolerance:
+		return "regressing", &change
+	default:
+		return "stable", &change
+	}
+}
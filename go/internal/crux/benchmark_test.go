@@ -0,0 +1,67 @@
+package crux
+
+import "testing"
+
+func TestNewBenchmark_RanksOriginsPerMetricAndDerivesTrends(t *testing.T) {
+	t.Parallel()
+
+	ours := &Result{Metrics: map[string]Metric{
+		"largest_contentful_paint": {
+			P75: number(3100),
+			Histogram: []HistogramBin{
+				{Start: number(0), End: number(2500), Density: 0.58},
+				{Start: number(2500), End: number(4000), Density: 0.30},
+				{Start: number(4000), Density: 0.12},
+			},
+		},
+		"cumulative_layout_shift":   {P75: number(0.05)},
+		"interaction_to_next_paint": {P75: number(150)},
+	}}
+	competitor := &Result{Metrics: map[string]Metric{
+		"largest_contentful_paint": {P75: number(2100)},
+		"cumulative_layout_shift":  {P75: number(0.02)},
+	}}
+	noData := &Result{Metrics: map[string]Metric{}}
+	history := &HistoryResult{Metrics: map[string]HistoryMetric{
+		"largest_contentful_paint": {P75: []*float64{number(9000), number(3600), nil, number(3100)}},
+	}}
+
+	benchmark := NewBenchmark([]BenchmarkInput{
+		{Origin: "https://ours.test", Primary: true, Current: ours, History: history},
+		{Origin: "https://rival.test", Current: competitor},
+		{Origin: "https://quiet.test", Current: noData},
+	}, 3)
+
+	lcp := benchmark.Metrics["largest_contentful_paint"]
+	if lcp[0].Origin != "https://rival.test" || lcp[1].Origin != "https://ours.test" || lcp[2].P75 != nil {
+		t.Fatalf("LCP ranking = %+v, want rival, ours, then no data", lcp)
+	}
+	if lcp[1].Passes == nil || *lcp[1].Passes {
+		t.Errorf("our LCP pass = %v, want false", lcp[1].Passes)
+	}
+	if lcp[1].GoodProportion == nil || *lcp[1].GoodProportion != 0.58 {
+		t.Errorf("our LCP good proportion = %v, want 0.58", lcp[1].GoodProportion)
+	}
+	if lcp[1].Trend != "improving" {
+		t.Errorf("our LCP trend = %q, want improving within the last 3 periods", lcp[1].Trend)
+	}
+	if lcp[0].Trend != "insufficient_data" {
+		t.Errorf("rival LCP trend = %q, want insufficient_data without history", lcp[0].Trend)
+	}
+
+	origins := benchmark.Origins
+	if origins[0].Origin != "https://rival.test" || origins[0].PassesCoreWebVitals == nil ||
+		!*origins[0].PassesCoreWebVitals {
+		t.Errorf("first origin = %+v, want passing rival without INP data", origins[0])
+	}
+	if origins[1].Origin != "https://ours.test" || *origins[1].PassesCoreWebVitals {
+		t.Errorf("second origin = %+v, want failing primary origin", origins[1])
+	}
+	if origins[2].PassesCoreWebVitals != nil {
+		t.Errorf("origin without data verdict = %v, want null", *origins[2].PassesCoreWebVitals)
+	}
+}
+
+func number(value float64) *float64 {
+	return &value
+}
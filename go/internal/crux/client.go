@@ -4,10 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
-	"regexp"
 	"strings"
 	"time"
 
@@ -18,10 +18,9 @@ const (
 	defaultCurrentAPIURL = "https://chromeuxreport.googleapis.com/v1/records:queryRecord"
 	defaultHistoryAPIURL = "https://chromeuxreport.googleapis.com/v1/records:queryHistoryRecord"
 	httpTimeout          = 30 * time.Second
+	serviceName          = "CrUX API"
 )
 
-var metricNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
-
 // Client calls the current and historical Chrome UX Report APIs.
 type Client struct {
 	apiKey        string
@@ -52,6 +51,9 @@ type QueryRequest struct {
 	Metrics []string
 	// CollectionPeriodCount limits history results to between 1 and 40 periods.
 	CollectionPeriodCount int
+	// OriginFallback retries a URL target against its origin when CrUX has no
+	// URL-level record.
+	OriginFallback bool
 }
 
 // NewQueryRequest validates and normalizes Chrome UX Report tool input.
@@ -98,9 +100,9 @@ func NewQueryRequest(
 	normalizedMetrics := make([]string, 0, len(metrics))
 	seen := make(map[string]struct{}, len(metrics))
 	for _, metric := range metrics {
-		metric = strings.ToLower(strings.TrimSpace(metric))
-		if !metricNamePattern.MatchString(metric) {
-			return QueryRequest{}, fmt.Errorf("metric %q is not a valid CrUX metric name", metric)
+		metric, err := ResolveMetricName(metric)
+		if err != nil {
+			return QueryRequest{}, err
 		}
 		if _, duplicate := seen[metric]; duplicate {
 			continue
@@ -122,8 +124,72 @@ func NewQueryRequest(
 	}, nil
 }
 
-// QueryCurrent returns current 28-day Chrome UX Report data.
+// OriginRequest returns the equivalent origin-level request for a URL target.
+// It reports false when the request already targets an origin.
+func (r QueryRequest) OriginRequest() (QueryRequest, bool) {
+	if r.TargetType != "url" {
+		return QueryRequest{}, false
+	}
+	parsedTarget, err := url.Parse(r.Target)
+	if err != nil || parsedTarget.Host == "" {
+		return QueryRequest{}, false
+	}
+
+	origin := r
+	origin.Target = parsedTarget.Scheme + "://" + parsedTarget.Host
+	origin.TargetType = "origin"
+	origin.Metrics = append([]string(nil), r.Metrics...)
+	origin.OriginFallback = false
+	return origin, true
+}
+
+// QueryCurrent returns current 28-day Chrome UX Report data. When the request
+// enables OriginFallback and CrUX has no URL-level record, the origin record is
+// returned and marked as a fallback.
 func (c *Client) QueryCurrent(ctx context.Context, request QueryRequest) (*Result, error) {
+	return QueryWithOriginFallback(ctx, request, c.queryCurrent)
+}
+
+// QueryHistory returns Chrome UX Report timeseries data. OriginFallback behaves
+// as it does for QueryCurrent.
+func (c *Client) QueryHistory(ctx context.Context, request QueryRequest) (*HistoryResult, error) {
+	return QueryWithOriginFallback(ctx, request, c.queryHistory)
+}
+
+// originFallbackResult is a CrUX result that can stand in for a URL target.
+type originFallbackResult interface {
+	markOriginFallback(requestedTarget string)
+}
+
+// QueryWithOriginFallback runs query for the request. When the request enables
+// OriginFallback and CrUX has no URL-level record, it runs query again for the
+// origin and marks the result as a fallback. query always receives a request
+// with OriginFallback cleared, so each call sends exactly one API request.
+func QueryWithOriginFallback[T originFallbackResult](
+	ctx context.Context,
+	request QueryRequest,
+	query func(context.Context, QueryRequest) (T, error),
+) (T, error) {
+	direct := request
+	direct.OriginFallback = false
+	result, err := query(ctx, direct)
+	if err == nil || !request.OriginFallback || !IsNotFound(err) {
+		return result, err
+	}
+	var none T
+	origin, ok := request.OriginRequest()
+	if !ok {
+		return none, err
+	}
+	result, err = query(ctx, origin)
+	if err != nil {
+		return none, fmt.Errorf("querying CrUX origin fallback %s: %w", origin.Target, err)
+	}
+	result.markOriginFallback(request.Target)
+	return result, nil
+}
+
+func (c *Client) queryCurrent(ctx context.Context, request QueryRequest) (*Result, error) {
 	var raw rawResponse
 	if err := c.post(ctx, c.currentAPIURL, request, false, &raw); err != nil {
 		return nil, err
@@ -135,8 +201,7 @@ func (c *Client) QueryCurrent(ctx context.Context, request QueryRequest) (*Resul
 	return result, nil
 }
 
-// QueryHistory returns Chrome UX Report timeseries data.
-func (c *Client) QueryHistory(ctx context.Context, request QueryRequest) (*HistoryResult, error) {
+func (c *Client) queryHistory(ctx context.Context, request QueryRequest) (*HistoryResult, error) {
 	var raw rawHistoryResponse
 	if err := c.post(ctx, c.historyAPIURL, request, true, &raw); err != nil {
 		return nil, err
@@ -148,6 +213,14 @@ func (c *Client) QueryHistory(ctx context.Context, request QueryRequest) (*Histo
 	return result, nil
 }
 
+// IsNotFound reports whether err is a CrUX response for a target without data.
+func IsNotFound(err error) bool {
+	var statusError *apihttp.StatusError
+	return errors.As(err, &statusError) &&
+		statusError.Service == serviceName &&
+		statusError.StatusCode == http.StatusNotFound
+}
+
 func (c *Client) post(
 	ctx context.Context,
 	endpoint string,
@@ -201,21 +274,10 @@ func (c *Client) post(
 		return fmt.Errorf("executing CrUX request: %w", err)
 	}
 	if response.StatusCode != http.StatusOK {
-		return &apihttp.StatusError{
-			Service:     "CrUX API",
-			StatusCode:  response.StatusCode,
-			BodySnippet: truncate(string(response.Body), 500),
-		}
+		return apihttp.NewStatusError(serviceName, response, 500)
 	}
 	if err := json.Unmarshal(response.Body, output); err != nil {
 		return fmt.Errorf("parsing CrUX response: %w", err)
 	}
 	return nil
 }
-
-func truncate(value string, maxLength int) string {
-	if len(value) <= maxLength {
-		return value
-	}
-	return value[:maxLength] + "..."
-}
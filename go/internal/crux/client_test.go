@@ -155,3 +155,81 @@ func readFixture(t *testing.T, name string) []byte {
 	}
 	return data
 }
+
+func TestOriginRequest_DerivesOriginFromURLTarget(t *testing.T) {
+	t.Parallel()
+
+	request, err := NewQueryRequest(
+		"https://Example.test:8443/path/page?q=1",
+		"url",
+		"phone",
+		[]string{"largest_contentful_paint"},
+		0,
+	)
+	if err != nil {
+		t.Fatalf("NewQueryRequest: %v", err)
+	}
+
+	origin, ok := request.OriginRequest()
+	if !ok {
+		t.Fatal("OriginRequest returned false for URL target")
+	}
+	if origin.Target != "https://Example.test:8443" || origin.TargetType != "origin" {
+		t.Errorf("origin = %q (%s), want scheme and host", origin.Target, origin.TargetType)
+	}
+	if origin.FormFactor != "phone" || len(origin.Metrics) != 1 {
+		t.Errorf("origin request did not preserve controls: %+v", origin)
+	}
+	if _, ok := origin.OriginRequest(); ok {
+		t.Error("OriginRequest must return false for origin targets")
+	}
+}
+
+func TestQueryCurrent_OriginFallback_RetriesOriginAfterNotFound(t *testing.T) {
+	t.Parallel()
+
+	fixture := readFixture(t, "crux-current.json")
+	var requestBodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Errorf("decode request body: %v", err)
+		}
+		requestBodies = append(requestBodies, requestBody)
+		w.Header().Set("Content-Type", "application/json")
+		if _, ok := requestBody["url"]; ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"chrome ux report data not found","status":"NOT_FOUND"}}`))
+			return
+		}
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		apiKey:        "test-key",
+		httpClient:    server.Client(),
+		currentAPIURL: server.URL,
+		historyAPIURL: server.URL,
+	}
+	request, err := NewQueryRequest("https://example.test/quiet", "url", "", nil, 0)
+	if err != nil {
+		t.Fatalf("NewQueryRequest: %v", err)
+	}
+
+	if _, err := client.QueryCurrent(context.Background(), request); !IsNotFound(err) {
+		t.Fatalf("QueryCurrent without fallback error = %v, want not found", err)
+	}
+
+	request.OriginFallback = true
+	result, err := client.QueryCurrent(context.Background(), request)
+	if err != nil {
+		t.Fatalf("QueryCurrent with fallback: %v", err)
+	}
+	if !result.OriginFallback || result.RequestedTarget != "https://example.test/quiet" {
+		t.Errorf("fallback marker = %v (%q), want original URL", result.OriginFallback, result.RequestedTarget)
+	}
+	if got := requestBodies[len(requestBodies)-1]["origin"]; got != "https://example.test" {
+		t.Errorf("fallback origin = %v, want https://example.test", got)
+	}
+}
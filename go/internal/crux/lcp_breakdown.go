@@ -0,0 +1,245 @@
+package crux
+
+const (
+	lcpMetricName         = "largest_contentful_paint"
+	lcpResourceTypeMetric = "largest_contentful_paint_resource_type"
+	subpartUnavailable    = "unavailable"
+)
+
+// LCPSubparts contains the LCP subpart names, in loading order, with their
+// CrUX metric names. The names match Lighthouse's lcp-breakdown-insight.
+var LCPSubparts = []struct {
+	Name   string
+	Metric string
+}{
+	{Name: "timeToFirstByte", Metric: "largest_contentful_paint_image_time_to_first_byte"},
+	{Name: "resourceLoadDelay", Metric: "largest_contentful_paint_image_resource_load_delay"},
+	{Name: "resourceLoadDuration", Metric: "largest_contentful_paint_image_resource_load_duration"},
+	{Name: "elementRenderDelay", Metric: "largest_contentful_paint_image_element_render_delay"},
+}
+
+var lcpSubpartAdvice = map[string]string{
+	"timeToFirstByte":      "The server response is slow; review redirects, server processing, and CDN caching.",
+	"resourceLoadDelay":    "The LCP image is discovered late; reference it in the initial HTML, preload it, or set fetchpriority=high and avoid lazy-loading it.",
+	"resourceLoadDuration": "The LCP image downloads slowly; compress and resize it, use a modern format, and serve it from a CDN.",
+	"elementRenderDelay":   "The LCP element renders late after loading; reduce render-blocking CSS and JavaScript and avoid client-side rendering of the element.",
+}
+
+// LCPBreakdown relates the LCP image subpart p75 values to the LCP p75.
+type LCPBreakdown struct {
+	// LCP is the LCP p75 in milliseconds.
+	LCP *float64 `json:"lcp"`
+	// ResourceTypes contains the share of page loads whose LCP element is an
+	// image or text. Subparts describe image LCPs only.
+	ResourceTypes map[string]float64 `json:"resourceTypes,omitempty"`
+	// Subparts contains each available subpart in loading order.
+	Subparts []LCPSubpart `json:"subparts"`
+	// DominantSubpart is the subpart with the largest p75.
+	DominantSubpart string `json:"dominantSubpart,omitempty"`
+	// Advice suggests how to reduce the dominant subpart.
+	Advice string `json:"advice,omitempty"`
+}
+
+// LCPSubpart contains one LCP image subpart p75.
+type LCPSubpart struct {
+	// Name is timeToFirstByte, resourceLoadDelay, resourceLoadDuration, or elementRenderDelay.
+	Name string `json:"name"`
+	// Metric is the CrUX metric name.
+	Metric string `json:"metric"`
+	// P75 is the subpart p75 in milliseconds.
+	P75 *float64 `json:"p75"`
+	// ShareOfLCP is P75 divided by the LCP p75. Each p75 is computed
+	// independently, so shares need not sum to one.
+	ShareOfLCP *float64 `json:"shareOfLcp,omitempty"`
+}
+
+// HistoryLCPBreakdown relates LCP image subpart p75 timeseries to the LCP p75
+// timeseries.
+type HistoryLCPBreakdown struct {
+	// LCP contains the ordered LCP p75 values; unavailable periods are null.
+	LCP []*float64 `json:"lcp"`
+	// Subparts contains each available subpart timeseries in loading order.
+	Subparts []HistoryLCPSubpart `json:"subparts"`
+	// DominantSubparts contains the dominant subpart of each period, or
+	// unavailable when no subpart has data.
+	DominantSubparts []string `json:"dominantSubparts"`
+}
+
+// HistoryLCPSubpart contains one LCP image subpart p75 timeseries.
+type HistoryLCPSubpart struct {
+	// Name is timeToFirstByte, resourceLoadDelay, resourceLoadDuration, or elementRenderDelay.
+	Name string `json:"name"`
+	// Metric is the CrUX metric name.
+	Metric string `json:"metric"`
+	// P75 contains ordered subpart p75 values; unavailable periods are null.
+	P75 []*float64 `json:"p75"`
+	// ShareOfLCP contains ordered shares of the LCP p75; unavailable periods are null.
+	ShareOfLCP []*float64 `json:"shareOfLcp"`
+}
+
+// LCPSubpartAdvice returns remediation guidance for an LCP subpart name.
+func LCPSubpartAdvice(name string) string {
+	return lcpSubpartAdvice[name]
+}
+
+func breakdownCurrent(metrics map[string]Metric) *LCPBreakdown {
+	breakdown := &LCPBreakdown{
+		LCP:           cloneNumber(metrics[lcpMetricName].P75),
+		ResourceTypes: metrics[lcpResourceTypeMetric].Fractions,
+		Subparts:      make([]LCPSubpart, 0, len(LCPSubparts)),
+	}
+	var dominant *float64
+	for _, definition := range LCPSubparts {
+		metric, ok := metrics[definition.Metric]
+		if !ok || metric.P75 == nil {
+			continue
+		}
+		subpart := LCPSubpart{
+			Name:       definition.Name,
+			Metric:     definition.Metric,
+			P75:        cloneNumber(metric.P75),
+			ShareOfLCP: share(metric.P75, breakdown.LCP),
+		}
+		if dominant == nil || *subpart.P75 > *dominant {
+			dominant = subpart.P75
+			breakdown.DominantSubpart = subpart.Name
+		}
+		breakdown.Subparts = append(breakdown.Subparts, subpart)
+	}
+	if len(breakdown.Subparts) == 0 {
+		return nil
+	}
+	breakdown.Advice = lcpSubpartAdvice[breakdown.DominantSubpart]
+	return breakdown
+}
+
+func breakdownHistory(metrics map[string]HistoryMetric, periods int) *HistoryLCPBreakdown {
+	breakdown := &HistoryLCPBreakdown{
+		LCP:              metrics[lcpMetricName].P75,
+		Subparts:         make([]HistoryLCPSubpart, 0, len(LCPSubparts)),
+		DominantSubparts: make([]string, periods),
+	}
+	dominant := make([]*float64, periods)
+	for _, definition := range LCPSubparts {
+		metric, ok := metrics[definition.Metric]
+		if !ok || len(metric.P75) == 0 {
+			continue
+		}
+		subpart := HistoryLCPSubpart{
+			Name:       definition.Name,
+			Metric:     definition.Metric,
+			P75:        metric.P75,
+			ShareOfLCP: make([]*float64, len(metric.P75)),
+		}
+		for index, value := range metric.P75 {
+			var lcp *float64
+			if index < len(breakdown.LCP) {
+				lcp = breakdown.LCP[index]
+			}
+			subpart.ShareOfLCP[index] = share(value, lcp)
+			if value != nil && index < periods &&
+				(dominant[index] == nil || *value > *dominant[index]) {
+				dominant[index] = value
+				breakdown.DominantSubparts[index] = definition.Name
+			}
+		}
+		breakdown.Subparts = append(breakdown.Subparts, subpart)
+	}
+	if len(breakdown.Subparts) == 0 {
+		return nil
+	}
+	for index, name := range breakdown.DominantSubparts {
+		if name == "" {
+			breakdown.DominantSubparts[index] = subpartUnavailable
+		}
+	}
+	return breakdown
+}
+
+func share(part, total *float64) *float64 {
+	if part == nil || total == nil || *total == 0 {
+		return nil
+	}
+	value := *part / *total
+	return &value
+}
+
+// LCPLabComparison correlates a field LCP breakdown with a Lighthouse lab
+// breakdown of the same page.
+type LCPLabComparison struct {
+	// FieldDominantSubpart is the subpart with the largest field p75.
+	FieldDominantSubpart string `json:"fieldDominantSubpart,omitempty"`
+	// LabDominantSubpart is the subpart with the longest lab duration.
+	LabDominantSubpart string `json:"labDominantSubpart,omitempty"`
+	// DominantSubpartsAgree reports whether field and lab share a bottleneck.
+	DominantSubpartsAgree bool `json:"dominantSubpartsAgree"`
+	// Subparts compares each subpart available in either source.
+	Subparts []LCPSubpartComparison `json:"subparts"`
+	// Summary explains the comparison in one sentence.
+	Summary string `json:"summary"`
+}
+
+// LCPSubpartComparison compares one subpart between field and lab data.
+type LCPSubpartComparison struct {
+	// Name is the subpart name.
+	Name string `json:"name"`
+	// FieldP75 is the real-user p75 in milliseconds.
+	FieldP75 *float64 `json:"fieldP75"`
+	// LabDuration is the Lighthouse duration in milliseconds.
+	LabDuration *float64 `json:"labDuration"`
+	// FieldShareOfLCP is FieldP75 divided by the field LCP p75.
+	FieldShareOfLCP *float64 `json:"fieldShareOfLcp,omitempty"`
+	// LabShareOfLCP is LabDuration divided by the lab LCP.
+	LabShareOfLCP *float64 `json:"labShareOfLcp,omitempty"`
+}
+
+// CompareLCPBreakdown correlates field subparts with Lighthouse lab subpart
+// durations keyed by subpart name. It returns nil when either side is empty.
+func CompareLCPBreakdown(
+	field *LCPBreakdown,
+	labLCP *float64,
+	labDurations map[string]float64,
+) *LCPLabComparison {
+	if field == nil || len(field.Subparts) == 0 || len(labDurations) == 0 {
+		return nil
+	}
+
+	comparison := &LCPLabComparison{
+		FieldDominantSubpart: field.DominantSubpart,
+		Subparts:             make([]LCPSubpartComparison, 0, len(LCPSubparts)),
+	}
+	fieldP75s := make(map[string]*float64, len(field.Subparts))
+	for _, subpart := range field.Subparts {
+		fieldP75s[subpart.Name] = subpart.P75
+	}
+	var labDominant float64
+	for _, definition := range LCPSubparts {
+		entry := LCPSubpartComparison{
+			Name:     definition.Name,
+			FieldP75: cloneNumber(fieldP75s[definition.Name]),
+		}
+		entry.FieldShareOfLCP = share(entry.FieldP75, field.LCP)
+		if duration, ok := labDurations[definition.Name]; ok {
+			entry.LabDuration = &duration
+			entry.LabShareOfLCP = share(entry.LabDuration, labLCP)
+			if comparison.LabDominantSubpart == "" || duration > labDominant {
+				labDominant = duration
+				comparison.LabDominantSubpart = definition.Name
+			}
+		}
+		if entry.FieldP75 != nil || entry.LabDuration != nil {
+			comparison.Subparts = append(comparison.Subparts, entry)
+		}
+	}
+
+	comparison.DominantSubpartsAgree = comparison.FieldDominantSubpart == comparison.LabDominantSubpart
+	if comparison.DominantSubpartsAgree {
+		comparison.Summary = "Field and lab data agree that " + comparison.FieldDominantSubpart +
+			" dominates LCP. " + lcpSubpartAdvice[comparison.FieldDominantSubpart]
+	} else {
+		comparison.Summary = "Real users are held back most by " + comparison.FieldDominantSubpart +
+			", while the Lighthouse run is held back most by " + comparison.LabDominantSubpart +
+			". Prioritize the field bottleneck; the lab run reflects one simulated device and network."
+	}
+	return comparison
+}
@@ -0,0 +1,90 @@
+package crux
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseCurrentFixture_DerivesLCPBreakdown(t *testing.T) {
+	t.Parallel()
+
+	var raw rawResponse
+	loadFixture(t, "crux-current.json", &raw)
+	breakdown := parseCurrent(&raw).LCPBreakdown
+	if breakdown == nil {
+		t.Fatal("LCP breakdown must be derived from subpart metrics")
+	}
+	if len(breakdown.Subparts) != 4 || breakdown.Subparts[0].Name != "timeToFirstByte" {
+		t.Fatalf("subparts = %+v, want four subparts in loading order", breakdown.Subparts)
+	}
+	if breakdown.DominantSubpart != "resourceLoadDuration" || breakdown.Advice == "" {
+		t.Errorf("dominant = %q (%q), want resourceLoadDuration with advice",
+			breakdown.DominantSubpart, breakdown.Advice)
+	}
+	share := breakdown.Subparts[2].ShareOfLCP
+	if share == nil || math.Abs(*share-1100.0/3100.0) > 1e-9 {
+		t.Errorf("load duration share = %v, want 1100/3100", share)
+	}
+	if breakdown.ResourceTypes["image"] != 0.72 {
+		t.Errorf("resource types = %v, want image share 0.72", breakdown.ResourceTypes)
+	}
+}
+
+func TestParseHistoryFixture_DerivesDominantSubpartPerPeriod(t *testing.T) {
+	t.Parallel()
+
+	var raw rawHistoryResponse
+	loadFixture(t, "crux-history.json", &raw)
+	breakdown := parseHistory(&raw).LCPBreakdown
+	if breakdown == nil {
+		t.Fatal("LCP breakdown must be derived from subpart timeseries")
+	}
+	want := []string{"resourceLoadDelay", "unavailable", "resourceLoadDuration"}
+	if !reflect.DeepEqual(breakdown.DominantSubparts, want) {
+		t.Errorf("dominant subparts = %v, want %v", breakdown.DominantSubparts, want)
+	}
+	if shares := breakdown.Subparts[0].ShareOfLCP; shares[1] != nil {
+		t.Errorf("unavailable period share = %v, want null", *shares[1])
+	}
+}
+
+func TestParseCurrent_WithoutSubparts_OmitsLCPBreakdown(t *testing.T) {
+	t.Parallel()
+
+	if breakdown := breakdownCurrent(map[string]Metric{
+		"largest_contentful_paint": {P75: number(2000)},
+	}); breakdown != nil {
+		t.Errorf("breakdown = %+v, want nil without subparts", breakdown)
+	}
+}
+
+func TestCompareLCPBreakdown_ReportsDisagreement(t *testing.T) {
+	t.Parallel()
+
+	field := &LCPBreakdown{
+		LCP: number(3000),
+		Subparts: []LCPSubpart{
+			{Name: "timeToFirstByte", P75: number(1400)},
+			{Name: "resourceLoadDelay", P75: number(300)},
+		},
+		DominantSubpart: "timeToFirstByte",
+	}
+	comparison := CompareLCPBreakdown(field, number(2000), map[string]float64{
+		"timeToFirstByte":   200,
+		"resourceLoadDelay": 1200,
+	})
+	if comparison == nil {
+		t.Fatal("comparison must be returned when both sides have subparts")
+	}
+	if comparison.DominantSubpartsAgree || comparison.LabDominantSubpart != "resourceLoadDelay" {
+		t.Errorf("comparison = %+v, want disagreement with lab resourceLoadDelay", comparison)
+	}
+	if len(comparison.Subparts) != 2 || comparison.Subparts[1].LabShareOfLCP == nil ||
+		*comparison.Subparts[1].LabShareOfLCP != 0.6 {
+		t.Errorf("subparts = %+v, want lab share 0.6 for resourceLoadDelay", comparison.Subparts)
+	}
+	if CompareLCPBreakdown(field, nil, nil) != nil {
+		t.Error("comparison without lab subparts must be nil")
+	}
+}
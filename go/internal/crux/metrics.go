@@ -0,0 +1,234 @@
+package crux
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/webvitals"
+)
+
+// Metric aggregations returned by the CrUX API.
+const (
+	AggregationHistogram   = "histogram"
+	AggregationPercentiles = "percentiles"
+	AggregationFractions   = "fractions"
+)
+
+// maxSuggestionDistance is the largest edit distance offered as a correction.
+const maxSuggestionDistance = 3
+
+// MetricInfo describes one metric known to the CrUX API.
+type MetricInfo struct {
+	// Name is the CrUX API metric name.
+	Name string `json:"name"`
+	// Aliases contains accepted shorthand names such as lcp.
+	Aliases []string `json:"aliases,omitempty"`
+	// Unit is ms, unitless, or fraction.
+	Unit string `json:"unit"`
+	// Description summarizes what the metric measures.
+	Description string `json:"description"`
+	// Aggregations contains the data shapes the API returns for the metric.
+	Aggregations []string `json:"aggregations"`
+	// CoreWebVital reports whether the metric is part of the Core Web Vitals assessment.
+	CoreWebVital bool `json:"coreWebVital"`
+	// Thresholds contains the rating boundaries derived by the server, when known.
+	Thresholds *webvitals.Threshold `json:"thresholds,omitempty"`
+}
+
+var metricCatalog = []MetricInfo{
+	{
+		Name:         "largest_contentful_paint",
+		Aliases:      []string{"lcp"},
+		Unit:         "ms",
+		Description:  "Time until the largest image or text block in the viewport is rendered.",
+		Aggregations: []string{AggregationHistogram, AggregationPercentiles},
+		CoreWebVital: true,
+	},
+	{
+		Name:         "interaction_to_next_paint",
+		Aliases:      []string{"inp"},
+		Unit:         "ms",
+		Description:  "Latency of the slowest user interaction until the next frame is painted.",
+		Aggregations: []string{AggregationHistogram, AggregationPercentiles},
+		CoreWebVital: true,
+	},
+	{
+		Name:         "cumulative_layout_shift",
+		Aliases:      []string{"cls"},
+		Unit:         "unitless",
+		Description:  "Largest burst of unexpected layout shift during the page lifetime.",
+		Aggregations: []string{AggregationHistogram, AggregationPercentiles},
+		CoreWebVital: true,
+	},
+	{
+		Name:         "first_contentful_paint",
+		Aliases:      []string{"fcp"},
+		Unit:         "ms",
+		Description:  "Time until the first text or image is rendered.",
+		Aggregations: []string{AggregationHistogram, AggregationPercentiles},
+	},
+	{
+		Name:         "experimental_time_to_first_byte",
+		Aliases:      []string{"ttfb", "time_to_first_byte"},
+		Unit:         "ms",
+		Description:  "Time from navigation start until the first byte of the response arrives.",
+		Aggregations: []string{AggregationHistogram, AggregationPercentiles},
+	},
+	{
+		Name:         "round_trip_time",
+		Aliases:      []string{"rtt"},
+		Unit:         "ms",
+		Description:  "Estimated network round-trip time of the visitors' connections.",
+		Aggregations: []string{AggregationHistogram, AggregationPercentiles},
+	},
+	{
+		Name:         "largest_contentful_paint_resource_type",
+		Aliases:      []string{"lcp_resource_type"},
+		Unit:         "fraction",
+		Description:  "Share of page loads whose LCP element is text or an image.",
+		Aggregations: []string{AggregationFractions},
+	},
+	{
+		Name:         "largest_contentful_paint_image_time_to_first_byte",
+		Aliases:      []string{"lcp_ttfb"},
+		Unit:         "ms",
+		Description:  "LCP image subpart: time until the document's first byte.",
+		Aggregations: []string{AggregationPercentiles},
+	},
+	{
+		Name:         "largest_contentful_paint_image_resource_load_delay",
+		Aliases:      []string{"lcp_resource_load_delay"},
+		Unit:         "ms",
+		Description:  "LCP image subpart: delay between the first byte and the image request starting.",
+		Aggregations: []string{AggregationPercentiles},
+	},
+	{
+		Name:         "largest_contentful_paint_image_resource_load_duration",
+		Aliases:      []string{"lcp_resource_load_duration"},
+		Unit:         "ms",
+		Description:  "LCP image subpart: time spent downloading the image.",
+		Aggregations: []string{AggregationPercentiles},
+	},
+	{
+		Name:         "largest_contentful_paint_image_element_render_delay",
+		Aliases:      []string{"lcp_element_render_delay"},
+		Unit:         "ms",
+		Description:  "LCP image subpart: delay between the image finishing loading and being rendered.",
+		Aggregations: []string{AggregationPercentiles},
+	},
+	{
+		Name:         "navigation_types",
+		Unit:         "fraction",
+		Description:  "Share of page loads by navigation type, such as navigate, reload, or back_forward_cache.",
+		Aggregations: []string{AggregationFractions},
+	},
+	{
+		Name:         "form_factors",
+		Unit:         "fraction",
+		Description:  "Share of page loads by device type. Only returned when no form factor is requested.",
+		Aggregations: []string{AggregationFractions},
+	},
+}
+
+// metricAliases maps every accepted metric name and alias to its CrUX name.
+var metricAliases = buildMetricAliases()
+
+func buildMetricAliases() map[string]string {
+	aliases := make(map[string]string)
+	for _, info := range metricCatalog {
+		aliases[info.Name] = info.Name
+		for _, alias := range info.Aliases {
+			aliases[alias] = info.Name
+		}
+	}
+	return aliases
+}
+
+// Metrics returns the catalog of known CrUX metrics.
+func Metrics() []MetricInfo {
+	catalog := make([]MetricInfo, 0, len(metricCatalog))
+	for _, info := range metricCatalog {
+		if threshold, ok := metricThreshold(info.Name); ok {
+			info.Thresholds = &threshold
+		}
+		catalog = append(catalog, info)
+	}
+	return catalog
+}
+
+// LookupMetric returns the catalog entry for a CrUX metric name or alias.
+func LookupMetric(name string) (MetricInfo, bool) {
+	canonical, ok := metricAliases[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return MetricInfo{}, false
+	}
+	for _, info := range Metrics() {
+		if info.Name == canonical {
+			return info, true
+		}
+	}
+	return MetricInfo{}, false
+}
+
+// ResolveMetricName maps a CrUX metric name or alias to its API name. Unknown
+// names are rejected with the closest known name when one is similar.
+func ResolveMetricName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if canonical, ok := metricAliases[name]; ok {
+		return canonical, nil
+	}
+	if suggestion := suggestMetricName(name); suggestion != "" {
+		return "", fmt.Errorf("metric %q is not a known CrUX metric; did you mean %q?", name, suggestion)
+	}
+	return "", fmt.Errorf("metric %q is not a known CrUX metric", name)
+}
+
+// CompleteMetricNames returns the metric names and aliases starting with prefix.
+func CompleteMetricNames(prefix string) []string {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	names := make([]string, 0, len(metricAliases))
+	for name := range metricAliases {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func suggestMetricName(name string) string {
+	best := ""
+	bestDistance := maxSuggestionDistance + 1
+	for candidate, canonical := range metricAliases {
+		distance := editDistance(name, candidate)
+		if distance >= len(candidate) {
+			continue
+		}
+		if distance < bestDistance || (distance == bestDistance && canonical < best) {
+			best, bestDistance = canonical, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two names.
+func editDistance(left, right string) int {
+	previous := make([]int, len(right)+1)
+	current := make([]int, len(right)+1)
+	for index := range previous {
+		previous[index] = index
+	}
+	for i := 1; i <= len(left); i++ {
+		current[0] = i
+		for j := 1; j <= len(right); j++ {
+			cost := 1
+			if left[i-1] == right[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(right)]
+}
@@ -0,0 +1,97 @@
+package crux
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/webvitals"
+)
+
+func TestResolveMetricName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		{input: "LCP", want: "largest_contentful_paint"},
+		{input: " ttfb ", want: "experimental_time_to_first_byte"},
+		{input: "navigation_types", want: "navigation_types"},
+		{input: "largest_contentfull_paint", wantErr: `did you mean "largest_contentful_paint"`},
+		{input: "lpc", wantErr: `did you mean "largest_contentful_paint"`},
+		{input: "bogus", wantErr: "not a known CrUX metric"},
+	}
+
+	for _, test := range tests {
+		got, err := ResolveMetricName(test.input)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("ResolveMetricName(%q) error = %v, want %q", test.input, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("ResolveMetricName(%q) = %q, %v; want %q", test.input, got, err, test.want)
+		}
+	}
+}
+
+func TestNewQueryRequest_ResolvesMetricAliases(t *testing.T) {
+	t.Parallel()
+
+	request, err := NewQueryRequest(
+		"https://example.test",
+		"origin",
+		"",
+		[]string{"lcp", "largest_contentful_paint", "CLS"},
+		0,
+	)
+	if err != nil {
+		t.Fatalf("NewQueryRequest: %v", err)
+	}
+	want := []string{"largest_contentful_paint", "cumulative_layout_shift"}
+	if !slices.Equal(request.Metrics, want) {
+		t.Errorf("metrics = %v, want %v", request.Metrics, want)
+	}
+}
+
+func TestMetrics_DescribesCatalog(t *testing.T) {
+	t.Parallel()
+
+	info, ok := LookupMetric("inp")
+	if !ok {
+		t.Fatal("inp alias must resolve to a catalog entry")
+	}
+	if info.Name != "interaction_to_next_paint" || !info.CoreWebVital || info.Unit != "ms" {
+		t.Errorf("info = %+v, want INP Core Web Vital in ms", info)
+	}
+	if info.Thresholds == nil || info.Thresholds.Good != 200 {
+		t.Errorf("thresholds = %+v, want good INP at 200 ms", info.Thresholds)
+	}
+
+	rtt, ok := LookupMetric("rtt")
+	if !ok || rtt.CoreWebVital || rtt.Thresholds == nil || rtt.Thresholds.Good != 75 || rtt.Thresholds.Poor != 275 {
+		t.Errorf("round_trip_time = %+v, want CrUX histogram buckets at 75 and 275 ms", rtt)
+	}
+	if _, ok := webvitals.ThresholdFor("rtt"); ok {
+		t.Error("RTT buckets must not be listed as a Google web vitals threshold")
+	}
+
+	navigation, ok := LookupMetric("navigation_types")
+	if !ok || navigation.Thresholds != nil ||
+		!slices.Equal(navigation.Aggregations, []string{AggregationFractions}) {
+		t.Errorf("navigation_types = %+v, want fractions without thresholds", navigation)
+	}
+}
+
+func TestCompleteMetricNames(t *testing.T) {
+	t.Parallel()
+
+	got := CompleteMetricNames("LCP_R")
+	want := []string{"lcp_resource_load_delay", "lcp_resource_load_duration", "lcp_resource_type"}
+	if !slices.Equal(got, want) {
+		t.Errorf("completions = %v, want %v", got, want)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/webvitals"
 )
 
 // Result contains current Chrome UX Report data for one URL or origin.
@@ -14,12 +16,20 @@ type Result struct {
 	Target string `json:"target"`
 	// TargetType is url or origin.
 	TargetType string `json:"targetType"`
+	// RequestedTarget is the URL originally requested when OriginFallback is set.
+	RequestedTarget string `json:"requestedTarget,omitempty"`
+	// OriginFallback reports whether URL data was replaced with origin data.
+	OriginFallback bool `json:"originFallback"`
 	// FormFactor is phone, tablet, desktop, or empty for all form factors.
 	FormFactor string `json:"formFactor,omitempty"`
 	// CollectionPeriod is the 28-day aggregation window.
 	CollectionPeriod CollectionPeriod `json:"collectionPeriod"`
 	// Metrics contains every metric returned by CrUX.
 	Metrics map[string]Metric `json:"metrics"`
+	// Assessment is the Core Web Vitals verdict computed from the p75 values.
+	Assessment *webvitals.Assessment `json:"coreWebVitalsAssessment,omitempty"`
+	// LCPBreakdown relates the LCP image subparts to LCP when CrUX returns them.
+	LCPBreakdown *LCPBreakdown `json:"lcpBreakdown,omitempty"`
 	// URLNormalization describes URL normalization performed by CrUX.
 	URLNormalization *URLNormalization `json:"urlNormalization,omitempty"`
 }
@@ -30,12 +40,22 @@ type HistoryResult struct {
 	Target string `json:"target"`
 	// TargetType is url or origin.
 	TargetType string `json:"targetType"`
+	// RequestedTarget is the URL originally requested when OriginFallback is set.
+	RequestedTarget string `json:"requestedTarget,omitempty"`
+	// OriginFallback reports whether URL data was replaced with origin data.
+	OriginFallback bool `json:"originFallback"`
 	// FormFactor is phone, tablet, desktop, or empty for all form factors.
 	FormFactor string `json:"formFactor,omitempty"`
 	// CollectionPeriods contains the ordered 28-day aggregation windows.
 	CollectionPeriods []CollectionPeriod `json:"collectionPeriods"`
 	// Metrics contains every metric timeseries returned by CrUX.
 	Metrics map[string]HistoryMetric `json:"metrics"`
+	// Assessments contains the Core Web Vitals verdict for each collection
+	// period; periods without Core Web Vitals data are null.
+	Assessments []*webvitals.Assessment `json:"coreWebVitalsAssessments,omitempty"`
+	// LCPBreakdown relates the LCP image subpart timeseries to LCP when CrUX
+	// returns them.
+	LCPBreakdown *HistoryLCPBreakdown `json:"lcpBreakdown,omitempty"`
 }
 
 // CollectionPeriod identifies one CrUX aggregation window.
@@ -72,6 +92,22 @@ type Metric struct {
 	P75 *float64 `json:"p75,omitempty"`
 	// Fractions contains labeled proportions for categorical metrics.
 	Fractions map[string]float64 `json:"fractions,omitempty"`
+	// Rating is the good, needs-improvement, or poor rating of P75 for known metrics.
+	Rating string `json:"rating,omitempty"`
+	// Proportions contains the histogram density in each rating range for known metrics.
+	Proportions *RatingProportions `json:"proportions,omitempty"`
+	// EstimatedPercentiles contains requested percentiles interpolated from Histogram.
+	EstimatedPercentiles []PercentileEstimate `json:"estimatedPercentiles,omitempty"`
+}
+
+// RatingProportions contains the share of experiences in each rating range.
+type RatingProportions struct {
+	// Good is the share of experiences in the good range.
+	Good float64 `json:"good"`
+	// NeedsImprovement is the share of experiences in the needs-improvement range.
+	NeedsImprovement float64 `json:"needsImprovement"`
+	// Poor is the share of experiences in the poor range.
+	Poor float64 `json:"poor"`
 }
 
 // HistogramBin contains one current CrUX histogram bucket.
@@ -92,6 +128,15 @@ type HistoryMetric struct {
 	P75 []*float64 `json:"p75,omitempty"`
 	// Fractions contains ordered labeled proportions; unavailable periods are null.
 	Fractions map[string][]*float64 `json:"fractions,omitempty"`
+	// Ratings contains the ordered P75 ratings for known metrics; unavailable
+	// periods are null.
+	Ratings []*string `json:"ratings,omitempty"`
+	// Proportions contains ordered rating-range shares for known metrics;
+	// unavailable periods are null.
+	Proportions []*RatingProportions `json:"proportions,omitempty"`
+	// EstimatedPercentiles contains requested percentile timeseries
+	// interpolated from Histogram.
+	EstimatedPercentiles []HistoryPercentileEstimate `json:"estimatedPercentiles,omitempty"`
 }
 
 // HistoryHistogramBin contains one CrUX histogram bucket across collection periods.
@@ -218,11 +263,13 @@ func parseCurrent(raw *rawResponse) *Result {
 		if metric.Percentiles != nil {
 			p75 = cloneNumber(metric.Percentiles.P75.value)
 		}
-		metrics[id] = Metric{
+		current := Metric{
 			Histogram: histogram,
 			P75:       p75,
 			Fractions: metric.Fractions,
 		}
+		rateCurrent(id, &current)
+		metrics[id] = current
 	}
 
 	return &Result{
@@ -231,6 +278,8 @@ func parseCurrent(raw *rawResponse) *Result {
 		FormFactor:       normalizeFormFactor(raw.Record.Key.FormFactor),
 		CollectionPeriod: raw.Record.CollectionPeriod,
 		Metrics:          metrics,
+		Assessment:       assessCurrent(metrics),
+		LCPBreakdown:     breakdownCurrent(metrics),
 		URLNormalization: raw.URLNormalizationDetails,
 	}
 }
@@ -264,11 +313,13 @@ func parseHistory(raw *rawHistoryResponse) *HistoryResult {
 			fractions = nil
 		}
 
-		metrics[id] = HistoryMetric{
+		series := HistoryMetric{
 			Histogram: histogram,
 			P75:       p75,
 			Fractions: fractions,
 		}
+		rateHistory(id, &series, len(raw.Record.CollectionPeriods))
+		metrics[id] = series
 	}
 
 	return &HistoryResult{
@@ -277,6 +328,8 @@ func parseHistory(raw *rawHistoryResponse) *HistoryResult {
 		FormFactor:        normalizeFormFactor(raw.Record.Key.FormFactor),
 		CollectionPeriods: raw.Record.CollectionPeriods,
 		Metrics:           metrics,
+		Assessments:       assessHistory(metrics, len(raw.Record.CollectionPeriods)),
+		LCPBreakdown:      breakdownHistory(metrics, len(raw.Record.CollectionPeriods)),
 	}
 }
 
@@ -318,3 +371,13 @@ func cloneNumbers(values []numberValue) []*float64 {
 	}
 	return result
 }
+
+func (r *Result) markOriginFallback(requestedTarget string) {
+	r.OriginFallback = true
+	r.RequestedTarget = requestedTarget
+}
+
+func (r *HistoryResult) markOriginFallback(requestedTarget string) {
+	r.OriginFallback = true
+	r.RequestedTarget = requestedTarget
+}
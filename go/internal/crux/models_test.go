@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -36,6 +37,23 @@ func TestParseCurrentFixture_PreservesHistogramsPercentilesAndFractions(t *testi
 		result.URLNormalization.NormalizedURL != "https://example.test/page" {
 		t.Fatal("URL normalization details were not preserved")
 	}
+	lcp := result.Metrics["largest_contentful_paint"]
+	if lcp.Rating != "needs-improvement" || lcp.Proportions == nil ||
+		*lcp.Proportions != (RatingProportions{Good: 0.58, NeedsImprovement: 0.30, Poor: 0.12}) {
+		t.Errorf("LCP rating = %q, proportions = %+v", lcp.Rating, lcp.Proportions)
+	}
+	cls := result.Metrics["cumulative_layout_shift"]
+	if cls.Rating != "good" || cls.Proportions == nil || cls.Proportions.Good != 0.88 {
+		t.Errorf("CLS rating = %q, proportions = %+v", cls.Rating, cls.Proportions)
+	}
+	if navigation := result.Metrics["navigation_types"]; navigation.Rating != "" ||
+		navigation.Proportions != nil {
+		t.Error("categorical metrics must not be rated")
+	}
+	if result.Assessment == nil || result.Assessment.Status != "failed" ||
+		!reflect.DeepEqual(result.Assessment.FailingMetrics, []string{"lcp"}) {
+		t.Errorf("assessment = %+v, want failed on lcp", result.Assessment)
+	}
 }
 
 func TestParseHistoryFixture_ConvertsUnavailableValuesToNull(t *testing.T) {
@@ -58,6 +76,15 @@ func TestParseHistoryFixture_ConvertsUnavailableValuesToNull(t *testing.T) {
 	if got := lcp.Histogram[0].Densities[1]; got != nil {
 		t.Errorf("middle histogram density = %v, want nil", got)
 	}
+	if len(lcp.Ratings) != 3 || lcp.Ratings[1] != nil ||
+		lcp.Ratings[0] == nil || *lcp.Ratings[0] != "needs-improvement" ||
+		lcp.Ratings[2] == nil || *lcp.Ratings[2] != "needs-improvement" {
+		t.Errorf("LCP ratings = %v, want null middle period", lcp.Ratings)
+	}
+	if len(lcp.Proportions) != 3 || lcp.Proportions[1] != nil ||
+		lcp.Proportions[2] == nil || lcp.Proportions[2].Good != 0.62 {
+		t.Errorf("LCP proportions = %+v, want null middle period", lcp.Proportions)
+	}
 	cls := result.Metrics["cumulative_layout_shift"]
 	if got := cls.P75[0]; got == nil || *got != 0.09 {
 		t.Errorf("CLS first p75 = %v, want 0.09", got)
@@ -66,6 +93,20 @@ func TestParseHistoryFixture_ConvertsUnavailableValuesToNull(t *testing.T) {
 	if got := navigation.Fractions["navigate"][2]; got == nil || *got != 0.66 {
 		t.Errorf("navigate fraction = %v, want 0.66", got)
 	}
+
+	if len(result.Assessments) != 3 {
+		t.Fatalf("assessments = %d, want one per period", len(result.Assessments))
+	}
+	if got := result.Assessments[0]; got == nil || got.Status != "failed" {
+		t.Errorf("first period assessment = %+v, want failed", got)
+	}
+	if got := result.Assessments[1]; got != nil {
+		t.Errorf("unavailable period assessment = %+v, want nil", got)
+	}
+	if got := result.Assessments[2]; got == nil || got.Status != "failed" ||
+		got.Metrics["lcp"].Rating != "needs-improvement" {
+		t.Errorf("last period assessment = %+v, want LCP needs-improvement", got)
+	}
 }
 
 func loadFixture(t *testing.T, name string, output any) {
@@ -0,0 +1,251 @@
+package crux
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+const maxEstimatedPercentiles = 5
+
+// Percentile estimation methods.
+const (
+	// EstimateInterpolated marks a value interpolated inside a bounded bucket.
+	EstimateInterpolated = "interpolated"
+	// EstimateExtrapolated marks a value extrapolated into the open final
+	// bucket from the reported p75.
+	EstimateExtrapolated = "extrapolated"
+	// EstimateLowerBound marks a value that is only the start of the open
+	// final bucket; the true percentile is at least this value.
+	EstimateLowerBound = "lower_bound"
+	// EstimateUnavailable marks a period without histogram data.
+	EstimateUnavailable = "unavailable"
+)
+
+// PercentileEstimate is a percentile interpolated from a CrUX histogram.
+type PercentileEstimate struct {
+	// Percentile is the estimated percentile between 0 and 100.
+	Percentile float64 `json:"percentile"`
+	// Value is the estimated metric value.
+	Value *float64 `json:"value"`
+	// Estimated is always true; CrUX reports only p75 exactly.
+	Estimated bool `json:"estimated"`
+	// Method is interpolated, extrapolated, lower_bound, or unavailable.
+	Method string `json:"method"`
+}
+
+// HistoryPercentileEstimate is a percentile timeseries interpolated from CrUX
+// histogram timeseries.
+type HistoryPercentileEstimate struct {
+	// Percentile is the estimated percentile between 0 and 100.
+	Percentile float64 `json:"percentile"`
+	// Values contains ordered estimates; unavailable periods are null.
+	Values []*float64 `json:"values"`
+	// Estimated is always true; CrUX reports only p75 exactly.
+	Estimated bool `json:"estimated"`
+	// Methods contains the estimation method for each period.
+	Methods []string `json:"methods"`
+}
+
+// NormalizePercentiles validates, deduplicates, and sorts requested percentiles.
+func NormalizePercentiles(percentiles []float64) ([]float64, error) {
+	if len(percentiles) > maxEstimatedPercentiles {
+		return nil, fmt.Errorf("at most %d percentiles may be estimated", maxEstimatedPercentiles)
+	}
+	normalized := make([]float64, 0, len(percentiles))
+	seen := make(map[float64]struct{}, len(percentiles))
+	for _, percentile := range percentiles {
+		if percentile <= 0 || percentile >= 100 {
+			return nil, fmt.Errorf("percentiles must be greater than 0 and less than 100")
+		}
+		if _, duplicate := seen[percentile]; duplicate {
+			continue
+		}
+		seen[percentile] = struct{}{}
+		normalized = append(normalized, percentile)
+	}
+	sort.Float64s(normalized)
+	return normalized, nil
+}
+
+// EstimatePercentiles adds histogram-based percentile estimates to every
+// metric with a numeric histogram.
+func (r *Result) EstimatePercentiles(percentiles []float64) {
+	if len(percentiles) == 0 {
+		return
+	}
+	for name, metric := range r.Metrics {
+		if !numericHistogram(metric.Histogram) {
+			continue
+		}
+		metric.EstimatedPercentiles = make([]PercentileEstimate, 0, len(percentiles))
+		for _, percentile := range percentiles {
+			metric.EstimatedPercentiles = append(
+				metric.EstimatedPercentiles,
+				EstimatePercentile(metric.Histogram, metric.P75, percentile),
+			)
+		}
+		r.Metrics[name] = metric
+	}
+}
+
+// EstimatePercentiles adds histogram-based percentile estimates for every
+// collection period to every metric with a numeric histogram.
+func (r *HistoryResult) EstimatePercentiles(percentiles []float64) {
+	if len(percentiles) == 0 {
+		return
+	}
+	periods := len(r.CollectionPeriods)
+	for name, metric := range r.Metrics {
+		if len(metric.Histogram) == 0 || metric.Histogram[0].Start == nil {
+			continue
+		}
+		metric.EstimatedPercentiles = make([]HistoryPercentileEstimate, 0, len(percentiles))
+		for _, percentile := range percentiles {
+			series := HistoryPercentileEstimate{
+				Percentile: percentile,
+				Values:     make([]*float64, periods),
+				Estimated:  true,
+				Methods:    make([]string, periods),
+			}
+			for index := range periods {
+				histogram, ok := historyPeriodHistogram(metric.Histogram, index)
+				if !ok {
+					series.Methods[index] = EstimateUnavailable
+					continue
+				}
+				var p75 *float64
+				if index < len(metric.P75) {
+					p75 = metric.P75[index]
+				}
+				estimate := EstimatePercentile(histogram, p75, percentile)
+				series.Values[index] = estimate.Value
+				series.Methods[index] = estimate.Method
+			}
+			metric.EstimatedPercentiles = append(metric.EstimatedPercentiles, series)
+		}
+		r.Metrics[name] = metric
+	}
+}
+
+// EstimatePercentile linearly interpolates a percentile from histogram bucket
+// densities. The exact p75, when supplied, is used as an additional anchor so
+// that estimates above it can be extrapolated into the open final bucket.
+func EstimatePercentile(histogram []HistogramBin, p75 *float64, percentile float64) PercentileEstimate {
+	estimate := PercentileEstimate{
+		Percentile: percentile,
+		Estimated:  true,
+		Method:     EstimateUnavailable,
+	}
+	if !numericHistogram(histogram) {
+		return estimate
+	}
+
+	total := 0.0
+	for _, bin := range histogram {
+		total += bin.Density
+	}
+	if total <= 0 {
+		return estimate
+	}
+
+	anchors := []percentileAnchor{{fraction: 0, value: *histogram[0].Start}}
+	cumulative := 0.0
+	openStart := *histogram[0].Start
+	for _, bin := range histogram {
+		if bin.End == nil {
+			openStart = *bin.Start
+			break
+		}
+		cumulative += bin.Density / total
+		anchors = append(anchors, percentileAnchor{fraction: cumulative, value: *bin.End})
+		openStart = *bin.End
+	}
+	if p75 != nil {
+		last := anchors[len(anchors)-1]
+		if 0.75 > last.fraction && *p75 >= last.value {
+			anchors = append(anchors, percentileAnchor{fraction: 0.75, value: *p75})
+		} else {
+			anchors = insertAnchor(anchors, percentileAnchor{fraction: 0.75, value: *p75})
+		}
+	}
+
+	target := percentile / 100
+	for index := 1; index < len(anchors); index++ {
+		lower, upper := anchors[index-1], anchors[index]
+		if target > upper.fraction {
+			continue
+		}
+		value := lower.value
+		if upper.fraction > lower.fraction {
+			value += (target - lower.fraction) / (upper.fraction - lower.fraction) *
+				(upper.value - lower.value)
+		}
+		estimate.Value = &value
+		estimate.Method = EstimateInterpolated
+		return estimate
+	}
+
+	last := anchors[len(anchors)-1]
+	if len(anchors) >= 2 && last.value > openStart {
+		previous := anchors[len(anchors)-2]
+		value := last.value + (target-last.fraction)/(last.fraction-previous.fraction)*
+			(last.value-previous.value)
+		estimate.Value = &value
+		estimate.Method = EstimateExtrapolated
+		return estimate
+	}
+	value := openStart
+	estimate.Value = &value
+	estimate.Method = EstimateLowerBound
+	return estimate
+}
+
+// percentileAnchor maps a cumulative fraction of experiences to a metric value.
+type percentileAnchor struct {
+	fraction float64
+	value    float64
+}
+
+// insertAnchor inserts an anchor in fraction order when it keeps the anchors
+// monotonic, and otherwise leaves them unchanged.
+func insertAnchor(anchors []percentileAnchor, inserted percentileAnchor) []percentileAnchor {
+	for index := 1; index < len(anchors); index++ {
+		lower, upper := anchors[index-1], anchors[index]
+		if inserted.fraction <= lower.fraction || inserted.fraction >= upper.fraction {
+			continue
+		}
+		if inserted.value < lower.value || inserted.value > upper.value {
+			return anchors
+		}
+		return slices.Insert(anchors, index, inserted)
+	}
+	return anchors
+}
+
+func numericHistogram(histogram []HistogramBin) bool {
+	if len(histogram) == 0 {
+		return false
+	}
+	for _, bin := range histogram {
+		if bin.Start == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func historyPeriodHistogram(bins []HistoryHistogramBin, index int) ([]HistogramBin, bool) {
+	histogram := make([]HistogramBin, 0, len(bins))
+	for _, bin := range bins {
+		if index >= len(bin.Densities) || bin.Densities[index] == nil || bin.Start == nil {
+			return nil, false
+		}
+		histogram = append(histogram, HistogramBin{
+			Start:   bin.Start,
+			End:     bin.End,
+			Density: *bin.Densities[index],
+		})
+	}
+	return histogram, true
+}
@@ -0,0 +1,100 @@
+package crux
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimatePercentile_InterpolatesAroundReportedP75(t *testing.T) {
+	t.Parallel()
+
+	histogram := []HistogramBin{
+		{Start: number(0), End: number(2500), Density: 0.58},
+		{Start: number(2500), End: number(4000), Density: 0.30},
+		{Start: number(4000), Density: 0.12},
+	}
+
+	tests := []struct {
+		percentile float64
+		p75        *float64
+		want       float64
+		method     string
+	}{
+		{percentile: 50, p75: number(3100), want: 2155.172, method: EstimateInterpolated},
+		{percentile: 75, p75: number(3100), want: 3100, method: EstimateInterpolated},
+		{percentile: 80, p75: number(3100), want: 3446.154, method: EstimateInterpolated},
+		{percentile: 90, p75: number(3100), want: 4000, method: EstimateLowerBound},
+		{percentile: 75, p75: nil, want: 3350, method: EstimateInterpolated},
+	}
+
+	for _, test := range tests {
+		estimate := EstimatePercentile(histogram, test.p75, test.percentile)
+		if !estimate.Estimated || estimate.Method != test.method || estimate.Value == nil {
+			t.Errorf("p%v estimate = %+v, want %s value", test.percentile, estimate, test.method)
+			continue
+		}
+		if math.Abs(*estimate.Value-test.want) > 0.001 {
+			t.Errorf("p%v = %v, want %v", test.percentile, *estimate.Value, test.want)
+		}
+	}
+}
+
+func TestEstimatePercentile_ExtrapolatesFromP75InOpenBucket(t *testing.T) {
+	t.Parallel()
+
+	histogram := []HistogramBin{
+		{Start: number(0), End: number(200), Density: 0.5},
+		{Start: number(200), End: number(500), Density: 0.2},
+		{Start: number(500), Density: 0.3},
+	}
+
+	estimate := EstimatePercentile(histogram, number(600), 90)
+	if estimate.Method != EstimateExtrapolated || estimate.Value == nil {
+		t.Fatalf("estimate = %+v, want extrapolated value", estimate)
+	}
+	if math.Abs(*estimate.Value-900) > 0.001 {
+		t.Errorf("p90 = %v, want 900", *estimate.Value)
+	}
+}
+
+func TestHistoryEstimatePercentiles_MarksUnavailablePeriods(t *testing.T) {
+	t.Parallel()
+
+	var raw rawHistoryResponse
+	loadFixture(t, "crux-history.json", &raw)
+	result := parseHistory(&raw)
+	result.EstimatePercentiles([]float64{50})
+
+	estimates := result.Metrics["largest_contentful_paint"].EstimatedPercentiles
+	if len(estimates) != 1 || len(estimates[0].Values) != 3 {
+		t.Fatalf("estimates = %+v, want one series with three periods", estimates)
+	}
+	series := estimates[0]
+	if series.Values[1] != nil || series.Methods[1] != EstimateUnavailable {
+		t.Errorf("middle period = %v (%s), want unavailable", series.Values[1], series.Methods[1])
+	}
+	if series.Values[0] == nil || series.Methods[0] != EstimateInterpolated {
+		t.Errorf("first period = %v (%s), want interpolated", series.Values[0], series.Methods[0])
+	}
+	if _, ok := result.Metrics["navigation_types"]; !ok ||
+		result.Metrics["navigation_types"].EstimatedPercentiles != nil {
+		t.Error("categorical metrics must not receive percentile estimates")
+	}
+}
+
+func TestNormalizePercentiles(t *testing.T) {
+	t.Parallel()
+
+	got, err := NormalizePercentiles([]float64{90, 50, 90})
+	if err != nil {
+		t.Fatalf("NormalizePercentiles: %v", err)
+	}
+	if len(got) != 2 || got[0] != 50 || got[1] != 90 {
+		t.Errorf("percentiles = %v, want [50 90]", got)
+	}
+	for _, invalid := range [][]float64{{0}, {100}, {-5}, {10, 20, 30, 40, 50, 60}} {
+		if _, err := NormalizePercentiles(invalid); err == nil {
+			t.Errorf("NormalizePercentiles(%v) returned nil error", invalid)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package crux
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/webvitals"
+)
+
+const (
+	// changePointTolerance is the minimum relative shift in mean p75 reported
+	// as a change point.
+	changePointTolerance = 0.10
+	// changePointExplainedVariance is the minimum share of p75 variance a
+	// split must explain to be reported as a change point.
+	changePointExplainedVariance = 0.5
+	minChangePointSegment        = 2
+)
+
+// DefaultTrendWindows contains the default percentage-change windows in periods.
+var DefaultTrendWindows = []int{4, 12}
+
+// TrendAnalysis contains per-metric statistics over a CrUX history record.
+type TrendAnalysis struct {
+	// Target is the URL or origin represented by the record.
+	Target string `json:"target"`
+	// TargetType is url or origin.
+	TargetType string `json:"targetType"`
+	// RequestedTarget is the URL originally requested when OriginFallback is set.
+	RequestedTarget string `json:"requestedTarget,omitempty"`
+	// OriginFallback reports whether URL data was replaced with origin data.
+	OriginFallback bool `json:"originFallback"`
+	// FormFactor is phone, tablet, desktop, or empty for all form factors.
+	FormFactor string `json:"formFactor,omitempty"`
+	// CollectionPeriods contains the analyzed aggregation windows, oldest first.
+	CollectionPeriods []CollectionPeriod `json:"collectionPeriods"`
+	// Metrics contains trend statistics for every metric with p75 values.
+	Metrics map[string]MetricTrend `json:"metrics"`
+}
+
+// MetricTrend contains trend statistics for one p75 timeseries.
+type MetricTrend struct {
+	// AvailablePeriods counts the periods with a p75 value.
+	AvailablePeriods int `json:"availablePeriods"`
+	// Latest is the most recent available p75 value.
+	Latest *float64 `json:"latest"`
+	// SlopePerPeriod is the least-squares p75 change per collection period,
+	// fitted over available periods only.
+	SlopePerPeriod *float64 `json:"slopePerPeriod"`
+	// Direction is improving, regressing, stable, or insufficient_data.
+	Direction string `json:"direction"`
+	// Changes contains the p75 change over each requested window.
+	Changes []WindowChange `json:"changes"`
+	// ChangePoint identifies the period where a step change began, when one is detected.
+	ChangePoint *ChangePoint `json:"changePoint,omitempty"`
+	// CurrentStreak is the run of consecutive passing or failing periods ending
+	// at the latest available period. Known metrics only.
+	CurrentStreak *Streak `json:"currentStreak,omitempty"`
+	// LongestPassingStreak is the longest run of periods rated good.
+	LongestPassingStreak int `json:"longestPassingStreak"`
+	// LongestFailingStreak is the longest run of periods not rated good.
+	LongestFailingStreak int `json:"longestFailingStreak"`
+}
+
+// WindowChange compares the latest p75 with the value a number of periods earlier.
+type WindowChange struct {
+	// Periods is the requested window length.
+	Periods int `json:"periods"`
+	// From is the most recent available p75 at least Periods before the latest.
+	From *float64 `json:"from"`
+	// To is the latest available p75.
+	To *float64 `json:"to"`
+	// AbsoluteChange is To minus From.
+	AbsoluteChange *float64 `json:"absoluteChange"`
+	// PercentChange is the relative change in percent.
+	PercentChange *float64 `json:"percentChange"`
+}
+
+// ChangePoint describes a detected step change in a p75 timeseries.
+type ChangePoint struct {
+	// PeriodIndex is the zero-based index of the first period after the change.
+	PeriodIndex int `json:"periodIndex"`
+	// CollectionPeriod is the first collection period after the change.
+	CollectionPeriod CollectionPeriod `json:"collectionPeriod"`
+	// MeanBefore is the mean p75 before the change.
+	MeanBefore float64 `json:"meanBefore"`
+	// MeanAfter is the mean p75 from the change onward.
+	MeanAfter float64 `json:"meanAfter"`
+	// PercentChange is the relative shift in mean p75 in percent.
+	PercentChange float64 `json:"percentChange"`
+}
+
+// Streak describes consecutive passing or failing collection periods.
+type Streak struct {
+	// Status is passing or failing.
+	Status string `json:"status"`
+	// Periods is the number of consecutive available periods.
+	Periods int `json:"periods"`
+	// Since is the first collection period of the streak.
+	Since CollectionPeriod `json:"since"`
+}
+
+type trendPoint struct {
+	index int
+	value float64
+}
+
+// AnalyzeTrend computes slopes, window changes, change points, and pass/fail
+// streaks for every p75 timeseries in a history record. Unavailable periods
+// are skipped rather than treated as zero; streaks span them.
+func AnalyzeTrend(history *HistoryResult, windows []int) *TrendAnalysis {
+	if len(windows) == 0 {
+		windows = DefaultTrendWindows
+	}
+	analysis := &TrendAnalysis{
+		Target:            history.Target,
+		TargetType:        history.TargetType,
+		RequestedTarget:   history.RequestedTarget,
+		OriginFallback:    history.OriginFallback,
+		FormFactor:        history.FormFactor,
+		CollectionPeriods: history.CollectionPeriods,
+		Metrics:           make(map[string]MetricTrend, len(history.Metrics)),
+	}
+
+	for name, metric := range history.Metrics {
+		if len(metric.P75) == 0 {
+			continue
+		}
+		points := make([]trendPoint, 0, len(metric.P75))
+		for index, value := range metric.P75 {
+			if value != nil {
+				points = append(points, trendPoint{index: index, value: *value})
+			}
+		}
+
+		trend := MetricTrend{
+			AvailablePeriods: len(points),
+			Direction:        "insufficient_data",
+			Changes:          make([]WindowChange, 0, len(windows)),
+		}
+		if len(points) > 0 {
+			latest := points[len(points)-1].value
+			trend.Latest = &latest
+		}
+		if slope, ok := leastSquaresSlope(points); ok {
+			trend.SlopePerPeriod = &slope
+			trend.Direction = slopeDirection(points, slope)
+		}
+		for _, window := range windows {
+			trend.Changes = append(trend.Changes, windowChange(points, window))
+		}
+		if index, before, after, ok := detectChangePoint(points); ok &&
+			index < len(history.CollectionPeriods) {
+			trend.ChangePoint = &ChangePoint{
+				PeriodIndex:      index,
+				CollectionPeriod: history.CollectionPeriods[index],
+				MeanBefore:       before,
+				MeanAfter:        after,
+				PercentChange:    (after - before) / before * 100,
+			}
+		}
+		if threshold, ok := metricThreshold(name); ok {
+			applyStreaks(&trend, points, threshold, history.CollectionPeriods)
+		}
+		analysis.Metrics[name] = trend
+	}
+	return analysis
+}
+
+func leastSquaresSlope(points []trendPoint) (float64, bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+	var sumX, sumY float64
+	for _, point := range points {
+		sumX += float64(point.index)
+		sumY += point.value
+	}
+	meanX := sumX / float64(len(points))
+	meanY := sumY / float64(len(points))
+	var covariance, variance float64
+	for _, point := range points {
+		dx := float64(point.index) - meanX
+		covariance += dx * (point.value - meanY)
+		variance += dx * dx
+	}
+	if variance == 0 {
+		return 0, false
+	}
+	return covariance / variance, true
+}
+
+// slopeDirection classifies the fitted change across the available span
+// relative to the mean. Lower p75 values are better for every CrUX metric.
+func slopeDirection(points []trendPoint, slope float64) string {
+	var sum float64
+	for _, point := range points {
+		sum += point.value
+	}
+	mean := sum / float64(len(points))
+	if mean == 0 {
+		return "stable"
+	}
+	span := float64(points[len(points)-1].index - points[0].index)
+	change := slope * span / mean
+	switch {
+	case change <= -trendTolerance:
+		return "improving"
+	case change >= trendTolerance:
+		return "regressing"
+	default:
+		return "stable"
+	}
+}
+
+func windowChange(points []trendPoint, window int) WindowChange {
+	change := WindowChange{Periods: window}
+	if len(points) == 0 {
+		return change
+	}
+	latest := points[len(points)-1]
+	change.To = &latest.value
+
+	// Search backwards for the most recent available value at least window
+	// periods before the latest one.
+	position := sort.Search(len(points), func(i int) bool {
+		return points[i].index > latest.index-window
+	})
+	if position == 0 {
+		return change
+	}
+	from := points[position-1].value
+	absolute := latest.value - from
+	change.From = &from
+	change.AbsoluteChange = &absolute
+	if from != 0 {
+		percent := absolute / from * 100
+		change.PercentChange = &percent
+	}
+	return change
+}
+
+// detectChangePoint finds the single split that most reduces the squared
+// error of a two-mean model and reports it when the shift is material.
+func detectChangePoint(points []trendPoint) (int, float64, float64, bool) {
+	if len(points) < minChangePointSegment*2 {
+		return 0, 0, 0, false
+	}
+
+	var total float64
+	for _, point := range points {
+		total += point.value
+	}
+	mean := total / float64(len(points))
+	var totalSquaredError float64
+	for _, point := range points {
+		totalSquaredError += (point.value - mean) * (point.value - mean)
+	}
+	if totalSquaredError == 0 {
+		return 0, 0, 0, false
+	}
+
+	bestSplit := -1
+	bestError := math.Inf(1)
+	var bestBefore, bestAfter float64
+	for split := minChangePointSegment; split <= len(points)-minChangePointSegment; split++ {
+		before := meanOf(points[:split])
+		after := meanOf(points[split:])
+		squaredError := squaredErrorAround(points[:split], before) +
+			squaredErrorAround(points[split:], after)
+		if squaredError < bestError {
+			bestSplit, bestError = split, squaredError
+			bestBefore, bestAfter = before, after
+		}
+	}
+
+	explained := 1 - bestError/totalSquaredError
+	if bestSplit < 0 || bestBefore == 0 ||
+		explained < changePointExplainedVariance ||
+		math.Abs(bestAfter-bestBefore)/bestBefore < changePointTolerance {
+		return 0, 0, 0, false
+	}
+	return points[bestSplit].index, bestBefore, bestAfter, true
+}
+
+func meanOf(points []trendPoint) float64 {
+	var sum float64
+	for _, point := range points {
+		sum += point.value
+	}
+	return sum / float64(len(points))
+}
+
+func squaredErrorAround(points []trendPoint, mean float64) float64 {
+	var sum float64
+	for _, point := range points {
+		sum += (point.value - mean) * (point.value - mean)
+	}
+	return sum
+}
+
+func applyStreaks(
+	trend *MetricTrend,
+	points []trendPoint,
+	threshold webvitals.Threshold,
+	periods []CollectionPeriod,
+) {
+	var status string
+	var length, start int
+	for _, point := range points {
+		pointStatus := "failing"
+		if threshold.Rate(point.value) == webvitals.RatingGood {
+			pointStatus = "passing"
+		}
+		if pointStatus != status {
+			status, length, start = pointStatus, 0, point.index
+		}
+		length++
+		if status == "passing" {
+			trend.LongestPassingStreak = max(trend.LongestPassingStreak, length)
+		} else {
+			trend.LongestFailingStreak = max(trend.LongestFailingStreak, length)
+		}
+	}
+	if length == 0 || start >= len(periods) {
+		return
+	}
+	trend.CurrentStreak = &Streak{
+		Status:  status,
+		Periods: length,
+		Since:   periods[start],
+	}
+}
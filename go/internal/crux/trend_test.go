@@ -0,0 +1,96 @@
+package crux
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnalyzeTrend_DetectsStepChangeAcrossNullPeriods(t *testing.T) {
+	t.Parallel()
+
+	periods := make([]CollectionPeriod, 10)
+	for index := range periods {
+		periods[index].FirstDate.Day = index + 1
+	}
+	history := &HistoryResult{
+		Target:            "https://example.test",
+		TargetType:        "origin",
+		CollectionPeriods: periods,
+		Metrics: map[string]HistoryMetric{
+			"largest_contentful_paint": {P75: []*float64{
+				number(2200), number(2300), nil, number(2250), number(2200),
+				number(3400), number(3500), nil, number(3450), number(3500),
+			}},
+			"navigation_types": {Fractions: map[string][]*float64{"navigate": {number(0.6)}}},
+		},
+	}
+
+	analysis := AnalyzeTrend(history, []int{5})
+	if _, ok := analysis.Metrics["navigation_types"]; ok {
+		t.Error("metrics without p75 values must be skipped")
+	}
+	lcp, ok := analysis.Metrics["largest_contentful_paint"]
+	if !ok {
+		t.Fatal("LCP trend missing")
+	}
+	if lcp.AvailablePeriods != 8 || lcp.Latest == nil || *lcp.Latest != 3500 {
+		t.Errorf("available = %d, latest = %v", lcp.AvailablePeriods, lcp.Latest)
+	}
+	if lcp.Direction != "regressing" || lcp.SlopePerPeriod == nil || *lcp.SlopePerPeriod <= 0 {
+		t.Errorf("direction = %q, slope = %v, want regressing", lcp.Direction, lcp.SlopePerPeriod)
+	}
+	if lcp.ChangePoint == nil || lcp.ChangePoint.PeriodIndex != 5 ||
+		lcp.ChangePoint.CollectionPeriod.FirstDate.Day != 6 {
+		t.Fatalf("change point = %+v, want period index 5", lcp.ChangePoint)
+	}
+	if math.Abs(lcp.ChangePoint.MeanBefore-2237.5) > 0.001 {
+		t.Errorf("mean before = %v, want 2237.5", lcp.ChangePoint.MeanBefore)
+	}
+
+	change := lcp.Changes[0]
+	if change.From == nil || *change.From != 2200 || change.PercentChange == nil {
+		t.Fatalf("window change = %+v, want from period index 4", change)
+	}
+	if math.Abs(*change.PercentChange-59.0909) > 0.001 {
+		t.Errorf("percent change = %v, want about 59.09", *change.PercentChange)
+	}
+
+	if lcp.CurrentStreak == nil || lcp.CurrentStreak.Status != "failing" ||
+		lcp.CurrentStreak.Periods != 4 || lcp.CurrentStreak.Since.FirstDate.Day != 6 {
+		t.Errorf("current streak = %+v, want 4 failing periods", lcp.CurrentStreak)
+	}
+	if lcp.LongestPassingStreak != 4 || lcp.LongestFailingStreak != 4 {
+		t.Errorf("streaks = %d passing, %d failing", lcp.LongestPassingStreak, lcp.LongestFailingStreak)
+	}
+}
+
+func TestAnalyzeTrend_StableSeriesHasNoChangePoint(t *testing.T) {
+	t.Parallel()
+
+	history := &HistoryResult{
+		CollectionPeriods: make([]CollectionPeriod, 6),
+		Metrics: map[string]HistoryMetric{
+			"cumulative_layout_shift": {P75: []*float64{
+				number(0.05), number(0.06), number(0.05), number(0.06), number(0.05), number(0.06),
+			}},
+			"interaction_to_next_paint": {P75: []*float64{nil, nil, nil, nil, nil, number(180)}},
+		},
+	}
+
+	analysis := AnalyzeTrend(history, nil)
+	cls := analysis.Metrics["cumulative_layout_shift"]
+	if cls.ChangePoint != nil {
+		t.Errorf("change point = %+v, want none for noise", cls.ChangePoint)
+	}
+	if len(cls.Changes) != len(DefaultTrendWindows) {
+		t.Errorf("changes = %d, want default windows", len(cls.Changes))
+	}
+	if cls.Changes[1].From != nil {
+		t.Errorf("12-period change from = %v, want null for a 6-period history", *cls.Changes[1].From)
+	}
+
+	inp := analysis.Metrics["interaction_to_next_paint"]
+	if inp.Direction != "insufficient_data" || inp.SlopePerPeriod != nil {
+		t.Errorf("INP direction = %q, want insufficient_data for one value", inp.Direction)
+	}
+}
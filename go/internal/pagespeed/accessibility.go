@@ -0,0 +1,425 @@
+package pagespeed
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const accessibilityCategoryID = "accessibility"
+
+// WCAGCriterion is a WCAG 2.2 success criterion.
+type WCAGCriterion struct {
+	// ID is the success criterion number, such as 1.4.3.
+	ID string `json:"id"`
+	// Name is the success criterion name.
+	Name string `json:"name"`
+	// Level is A, AA, or AAA.
+	Level string `json:"level"`
+}
+
+// wcagCriteria lists the success criteria referenced by the Lighthouse
+// accessibility audits.
+var wcagCriteria = map[string]WCAGCriterion{
+	"1.1.1": {ID: "1.1.1", Name: "Non-text Content", Level: "A"},
+	"1.2.2": {ID: "1.2.2", Name: "Captions (Prerecorded)", Level: "A"},
+	"1.3.1": {ID: "1.3.1", Name: "Info and Relationships", Level: "A"},
+	"1.3.2": {ID: "1.3.2", Name: "Meaningful Sequence", Level: "A"},
+	"1.4.1": {ID: "1.4.1", Name: "Use of Color", Level: "A"},
+	"1.4.3": {ID: "1.4.3", Name: "Contrast (Minimum)", Level: "AA"},
+	"1.4.4": {ID: "1.4.4", Name: "Resize Text", Level: "AA"},
+	"2.1.1": {ID: "2.1.1", Name: "Keyboard", Level: "A"},
+	"2.1.2": {ID: "2.1.2", Name: "No Keyboard Trap", Level: "A"},
+	"2.2.1": {ID: "2.2.1", Name: "Timing Adjustable", Level: "A"},
+	"2.4.1": {ID: "2.4.1", Name: "Bypass Blocks", Level: "A"},
+	"2.4.2": {ID: "2.4.2", Name: "Page Titled", Level: "A"},
+	"2.4.3": {ID: "2.4.3", Name: "Focus Order", Level: "A"},
+	"2.4.4": {ID: "2.4.4", Name: "Link Purpose (In Context)", Level: "A"},
+	"2.4.9": {ID: "2.4.9", Name: "Link Purpose (Link Only)", Level: "AAA"},
+	"2.5.3": {ID: "2.5.3", Name: "Label in Name", Level: "A"},
+	"2.5.8": {ID: "2.5.8", Name: "Target Size (Minimum)", Level: "AA"},
+	"3.1.1": {ID: "3.1.1", Name: "Language of Page", Level: "A"},
+	"3.1.2": {ID: "3.1.2", Name: "Language of Parts", Level: "AA"},
+	"3.3.2": {ID: "3.3.2", Name: "Labels or Instructions", Level: "A"},
+	"4.1.2": {ID: "4.1.2", Name: "Name, Role, Value", Level: "A"},
+}
+
+// axeRuleCriteria maps the axe-core rule behind each Lighthouse accessibility
+// audit to the WCAG success criteria in its axe tags. Rules axe tags only as
+// best practice map to no criteria.
+var axeRuleCriteria = map[string][]string{
+	"accesskeys":                   nil,
+	"aria-allowed-attr":            {"4.1.2"},
+	"aria-allowed-role":            nil,
+	"aria-command-name":            {"4.1.2"},
+	"aria-conditional-attr":        {"4.1.2"},
+	"aria-deprecated-role":         {"4.1.2"},
+	"aria-dialog-name":             nil,
+	"aria-hidden-body":             {"4.1.2"},
+	"aria-hidden-focus":            {"4.1.2"},
+	"aria-input-field-name":        {"4.1.2"},
+	"aria-meter-name":              {"1.1.1"},
+	"aria-progressbar-name":        {"1.1.1"},
+	"aria-prohibited-attr":         {"4.1.2"},
+	"aria-required-attr":           {"4.1.2"},
+	"aria-required-children":       {"1.3.1"},
+	"aria-required-parent":         {"1.3.1"},
+	"aria-roles":                   {"4.1.2"},
+	"aria-text":                    nil,
+	"aria-toggle-field-name":       {"4.1.2"},
+	"aria-tooltip-name":            {"4.1.2"},
+	"aria-treeitem-name":           nil,
+	"aria-valid-attr":              {"4.1.2"},
+	"aria-valid-attr-value":        {"4.1.2"},
+	"button-name":                  {"4.1.2"},
+	"bypass":                       {"2.4.1"},
+	"color-contrast":               {"1.4.3"},
+	"definition-list":              {"1.3.1"},
+	"dlitem":                       {"1.3.1"},
+	"document-title":               {"2.4.2"},
+	"empty-heading":                nil,
+	"form-field-multiple-labels":   {"3.3.2"},
+	"frame-title":                  {"4.1.2"},
+	"heading-order":                nil,
+	"html-has-lang":                {"3.1.1"},
+	"html-lang-valid":              {"3.1.1"},
+	"html-xml-lang-mismatch":       {"3.1.1"},
+	"identical-links-same-purpose": {"2.4.9"},
+	"image-alt":                    {"1.1.1"},
+	"image-redundant-alt":          nil,
+	"input-button-name":            {"4.1.2"},
+	"input-image-alt":              {"1.1.1", "4.1.2"},
+	"label":                        {"4.1.2"},
+	"label-content-name-mismatch":  {"2.5.3"},
+	"landmark-one-main":            nil,
+	"link-in-text-block":           {"1.4.1"},
+	"link-name":                    {"2.4.4", "4.1.2"},
+	"list":                         {"1.3.1"},
+	"listitem":                     {"1.3.1"},
+	"meta-refresh":                 {"2.2.1"},
+	"meta-viewport":                {"1.4.4"},
+	"object-alt":                   {"1.1.1"},
+	"select-name":                  {"4.1.2"},
+	"skip-link":                    nil,
+	"tabindex":                     nil,
+	"table-duplicate-name":         nil,
+	"table-fake-caption":           {"1.3.1"},
+	"target-size":                  {"2.5.8"},
+	"td-has-header":                {"1.3.1"},
+	"td-headers-attr":              {"1.3.1"},
+	"th-has-data-cells":            {"1.3.1"},
+	"valid-lang":                   {"3.1.2"},
+	"video-caption":                {"1.2.2"},
+}
+
+// manualAccessibilityChecks describes the Lighthouse accessibility audits
+// that require human verification. LabData keeps only their IDs.
+var manualAccessibilityChecks = map[string]ManualCheck{
+	"custom-controls-labels":         {Title: "Custom controls have associated labels", Criteria: []string{"4.1.2"}},
+	"custom-controls-roles":          {Title: "Custom controls have ARIA roles", Criteria: []string{"4.1.2"}},
+	"focus-traps":                    {Title: "User focus is not accidentally trapped in a region", Criteria: []string{"2.1.2"}},
+	"focusable-controls":             {Title: "Interactive controls are keyboard focusable", Criteria: []string{"2.1.1"}},
+	"interactive-element-affordance": {Title: "Interactive elements indicate their purpose and state"},
+	"logical-tab-order":              {Title: "The page has a logical tab order", Criteria: []string{"2.4.3"}},
+	"managed-focus":                  {Title: "The user's focus is directed to new content added to the page", Criteria: []string{"2.4.3"}},
+	"offscreen-content-hidden":       {Title: "Offscreen content is hidden from assistive technology"},
+	"use-landmarks":                  {Title: "HTML5 landmark elements are used to improve navigation", Criteria: []string{"1.3.1"}},
+	"visual-order-follows-dom":       {Title: "Visual order on the page follows DOM order", Criteria: []string{"1.3.2"}},
+}
+
+// AccessibilityReport groups failing accessibility audits by WCAG success
+// criterion.
+type AccessibilityReport struct {
+	// Score is the accessibility category score when Lighthouse ran it.
+	Score *float64 `json:"score,omitempty"`
+	// FailingAudits is the number of failing accessibility audits.
+	FailingAudits int `json:"failingAudits"`
+	// FailingNodes is the number of failing elements across audits.
+	FailingNodes int `json:"failingNodes"`
+	// Criteria groups failing audits by success criterion, level A first.
+	Criteria []CriterionFailures `json:"criteria"`
+	// BestPractices lists failing audits that map to no WCAG criterion.
+	BestPractices []AccessibilityAudit `json:"bestPractices"`
+	// ManualChecks lists the checks Lighthouse cannot automate.
+	ManualChecks []ManualCheck `json:"manualChecks"`
+}
+
+// CriterionFailures contains the failing audits for one success criterion.
+type CriterionFailures struct {
+	WCAGCriterion
+	// Audits lists the failing audits that test the criterion.
+	Audits []AccessibilityAudit `json:"audits"`
+}
+
+// AccessibilityAudit is one failing accessibility audit.
+type AccessibilityAudit struct {
+	// ID is the Lighthouse audit and axe rule identifier.
+	ID string `json:"id"`
+	// Title is the audit title.
+	Title string `json:"title"`
+	// Weight is the audit weight in the accessibility score.
+	Weight float64 `json:"weight,omitempty"`
+	// Criteria lists the WCAG success criteria the audit tests.
+	Criteria []string `json:"criteria,omitempty"`
+	// Nodes lists the failing elements.
+	Nodes []FailingNode `json:"nodes"`
+}
+
+// FailingNode is an element that failed an accessibility audit.
+type FailingNode struct {
+	// Selector is the element's CSS selector.
+	Selector string `json:"selector"`
+	// Snippet is the element's opening HTML tag.
+	Snippet string `json:"snippet,omitempty"`
+	// NodeLabel is a readable label for the element.
+	NodeLabel string `json:"nodeLabel,omitempty"`
+	// Explanation describes how to fix the failure.
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// ManualCheck is an accessibility check that needs human verification.
+type ManualCheck struct {
+	// ID is the Lighthouse audit identifier.
+	ID string `json:"id"`
+	// Title describes what to verify.
+	Title string `json:"title"`
+	// Criteria lists the related WCAG success criteria.
+	Criteria []string `json:"criteria,omitempty"`
+}
+
+// ParseAccessibilityReport builds the accessibility report from the failing
+// audits in lab. An audit belongs to the report when it is weighted in the
+// accessibility category or is a known axe rule. It fails when lab has no
+// accessibility category and no accessibility audits.
+func ParseAccessibilityReport(lab *LabData) (*AccessibilityReport, error) {
+	if lab == nil {
+		return nil, fmt.Errorf("lighthouse result has no lab data")
+	}
+	report := &AccessibilityReport{
+		Criteria:      make([]CriterionFailures, 0),
+		BestPractices: make([]AccessibilityAudit, 0),
+		ManualChecks:  make([]ManualCheck, 0),
+	}
+	weights := make(map[string]float64)
+	category, hasCategory := lab.Categories[accessibilityCategoryID]
+	if hasCategory {
+		report.Score = category.Score
+		if category.Breakdown != nil {
+			for _, audit := range category.Breakdown.Audits {
+				weights[audit.ID] = audit.Weight
+			}
+		}
+	}
+
+	groups := make(map[string]*CriterionFailures)
+	found := false
+	for _, audits := range [][]LighthouseAudit{lab.Diagnostics, lab.UnscoredAudits} {
+		for _, audit := range audits {
+			criteria, isRule := axeRuleCriteria[audit.ID]
+			if _, weighted := weights[audit.ID]; !isRule && !weighted {
+				continue
+			}
+			found = true
+			if audit.Score == nil || *audit.Score >= 0.9 {
+				continue
+			}
+			failing := AccessibilityAudit{
+				ID:       audit.ID,
+				Title:    audit.Title,
+				Weight:   weights[audit.ID],
+				Criteria: criteria,
+				Nodes:    failingNodes(audit),
+			}
+			report.FailingAudits++
+			report.FailingNodes += len(failing.Nodes)
+			if len(criteria) == 0 {
+				report.BestPractices = append(report.BestPractices, failing)
+				continue
+			}
+			for _, id := range criteria {
+				group, ok := groups[id]
+				if !ok {
+					group = &CriterionFailures{WCAGCriterion: wcagCriteria[id]}
+					groups[id] = group
+				}
+				group.Audits = append(group.Audits, failing)
+			}
+		}
+	}
+
+	for _, id := range lab.ManualAuditIDs {
+		check, ok := manualAccessibilityChecks[id]
+		if !ok {
+			continue
+		}
+		check.ID = id
+		report.ManualChecks = append(report.ManualChecks, check)
+	}
+	if !hasCategory && !found && len(report.ManualChecks) == 0 {
+		return nil, fmt.Errorf("lighthouse result has no accessibility audits")
+	}
+
+	for _, group := range groups {
+		sortAccessibilityAudits(group.Audits)
+		report.Criteria = append(report.Criteria, *group)
+	}
+	sort.Slice(report.Criteria, func(i, j int) bool {
+		if report.Criteria[i].Level != report.Criteria[j].Level {
+			return len(report.Criteria[i].Level) < len(report.Criteria[j].Level)
+		}
+		return compareCriterionIDs(report.Criteria[i].ID, report.Criteria[j].ID) < 0
+	})
+	sortAccessibilityAudits(report.BestPractices)
+	return report, nil
+}
+
+func failingNodes(audit LighthouseAudit) []FailingNode {
+	nodes := make([]FailingNode, 0)
+	details, err := audit.DecodeDetails()
+	if err != nil {
+		return nodes
+	}
+	table, ok := details.(*TableDetails)
+	if !ok {
+		return nodes
+	}
+	for _, item := range table.Items {
+		node, ok := item.Node("node")
+		if !ok {
+			continue
+		}
+		nodes = append(nodes, FailingNode{
+			Selector:    node.Selector,
+			Snippet:     node.Snippet,
+			NodeLabel:   node.NodeLabel,
+			Explanation: node.Explanation,
+		})
+	}
+	return nodes
+}
+
+func sortAccessibilityAudits(audits []AccessibilityAudit) {
+	sort.SliceStable(audits, func(i, j int) bool {
+		if audits[i].Weight != audits[j].Weight {
+			return audits[i].Weight > audits[j].Weight
+		}
+		return audits[i].ID < audits[j].ID
+	})
+}
+
+// compareCriterionIDs orders success criterion numbers numerically so that
+// 1.4.10 follows 1.4.3.
+func compareCriterionIDs(left, right string) int {
+	leftParts := strings.Split(left, ".")
+	rightParts := strings.Split(right, ".")
+	for index := 0; index < len(leftParts) && index < len(rightParts); index++ {
+		if len(leftParts[index]) != len(rightParts[index]) {
+			return len(leftParts[index]) - len(rightParts[index])
+		}
+		if comparison := strings.Compare(leftParts[index], rightParts[index]); comparison != 0 {
+			return comparison
+		}
+	}
+	return len(leftParts) - len(rightParts)
+}
+
+// AccessibilityDiff compares two accessibility reports, such as runs before
+// and after a deploy.
+type AccessibilityDiff struct {
+	// ScoreChange is the current score minus the baseline score.
+	ScoreChange *float64 `json:"scoreChange,omitempty"`
+	// FailingNodesChange is the change in failing elements.
+	FailingNodesChange int `json:"failingNodesChange"`
+	// Fixed lists audits that failed in the baseline and pass now.
+	Fixed []AccessibilityAuditChange `json:"fixed"`
+	// Regressed lists audits that fail now and passed in the baseline.
+	Regressed []AccessibilityAuditChange `json:"regressed"`
+	// Changed lists audits that fail in both runs with different elements.
+	Changed []AccessibilityAuditChange `json:"changed"`
+}
+
+// AccessibilityAuditChange describes how one audit's failing elements changed.
+type AccessibilityAuditChange struct {
+	// ID is the audit identifier.
+	ID string `json:"id"`
+	// Title is the audit title.
+	Title string `json:"title"`
+	// Criteria lists the WCAG success criteria the audit tests.
+	Criteria []string `json:"criteria,omitempty"`
+	// AddedNodes lists the selectors of newly failing elements.
+	AddedNodes []string `json:"addedNodes,omitempty"`
+	// RemovedNodes lists the selectors of elements that no longer fail.
+	RemovedNodes []string `json:"removedNodes,omitempty"`
+}
+
+// DiffAccessibilityReports compares the failing audits of a baseline and a
+// current report. Elements are matched by selector.
+func DiffAccessibilityReports(baseline, current *AccessibilityReport) *AccessibilityDiff {
+	diff := &AccessibilityDiff{
+		FailingNodesChange: current.FailingNodes - baseline.FailingNodes,
+		Fixed:              make([]AccessibilityAuditChange, 0),
+		Regressed:          make([]AccessibilityAuditChange, 0),
+		Changed:            make([]AccessibilityAuditChange, 0),
+	}
+	if baseline.Score != nil && current.Score != nil {
+		change := roundScore(*current.Score - *baseline.Score)
+		diff.ScoreChange = &change
+	}
+
+	before := baseline.failingAudits()
+	after := current.failingAudits()
+	for id, audit := range before {
+		if _, ok := after[id]; !ok {
+			diff.Fixed = append(diff.Fixed, auditChange(audit, audit.Nodes, nil))
+		}
+	}
+	for id, audit := range after {
+		previous, ok := before[id]
+		if !ok {
+			diff.Regressed = append(diff.Regressed, auditChange(audit, nil, audit.Nodes))
+			continue
+		}
+		change := auditChange(audit, previous.Nodes, audit.Nodes)
+		if len(change.AddedNodes) > 0 || len(change.RemovedNodes) > 0 {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for _, changes := range [][]AccessibilityAuditChange{diff.Fixed, diff.Regressed, diff.Changed} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	}
+	return diff
+}
+
+func (r *AccessibilityReport) failingAudits() map[string]AccessibilityAudit {
+	audits := make(map[string]AccessibilityAudit)
+	for _, group := range r.Criteria {
+		for _, audit := range group.Audits {
+			audits[audit.ID] = audit
+		}
+	}
+	for _, audit := range r.BestPractices {
+		audits[audit.ID] = audit
+	}
+	return audits
+}
+
+func auditChange(audit AccessibilityAudit, before, after []FailingNode) AccessibilityAuditChange {
+	change := AccessibilityAuditChange{ID: audit.ID, Title: audit.Title, Criteria: audit.Criteria}
+	beforeSelectors := make(map[string]struct{}, len(before))
+	for _, node := range before {
+		beforeSelectors[node.Selector] = struct{}{}
+	}
+	afterSelectors := make(map[string]struct{}, len(after))
+	for _, node := range after {
+		afterSelectors[node.Selector] = struct{}{}
+		if _, ok := beforeSelectors[node.Selector]; !ok {
+			change.AddedNodes = append(change.AddedNodes, node.Selector)
+		}
+	}
+	for _, node := range before {
+		if _, ok := afterSelectors[node.Selector]; !ok {
+			change.RemovedNodes = append(change.RemovedNodes, node.Selector)
+		}
+	}
+	return change
+}
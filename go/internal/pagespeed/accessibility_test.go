@@ -0,0 +1,115 @@
+package pagespeed
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAccessibilityReport_GroupsFailuresByCriterion(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	report, err := ParseAccessibilityReport(result.LabData)
+	if err != nil {
+		t.Fatalf("ParseAccessibilityReport: %v", err)
+	}
+	if report.Score == nil || *report.Score != 0.46 || report.FailingAudits != 3 || report.FailingNodes != 4 {
+		t.Errorf("report totals = score %v, %d audits, %d nodes", report.Score, report.FailingAudits, report.FailingNodes)
+	}
+
+	if len(report.Criteria) != 2 {
+		t.Fatalf("criteria = %+v, want 1.1.1 and 1.4.3", report.Criteria)
+	}
+	nonText := report.Criteria[0]
+	if nonText.ID != "1.1.1" || nonText.Level != "A" || nonText.Name != "Non-text Content" ||
+		len(nonText.Audits) != 1 || nonText.Audits[0].ID != "image-alt" || nonText.Audits[0].Weight != 10 {
+		t.Errorf("first criterion = %+v", nonText)
+	}
+	contrast := report.Criteria[1]
+	if contrast.ID != "1.4.3" || contrast.Level != "AA" || len(contrast.Audits[0].Nodes) != 2 {
+		t.Fatalf("second criterion = %+v", contrast)
+	}
+	node := contrast.Audits[0].Nodes[0]
+	if node.Selector != "footer > p.legal" || node.Snippet != `<p class="legal">` ||
+		!strings.Contains(node.Explanation, "insufficient color contrast of 2.52") {
+		t.Errorf("contrast node = %+v", node)
+	}
+
+	if len(report.BestPractices) != 1 || report.BestPractices[0].ID != "heading-order" {
+		t.Errorf("best practices = %+v", report.BestPractices)
+	}
+	wantManual := []ManualCheck{
+		{ID: "focus-traps", Title: "User focus is not accidentally trapped in a region", Criteria: []string{"2.1.2"}},
+		{ID: "logical-tab-order", Title: "The page has a logical tab order", Criteria: []string{"2.4.3"}},
+	}
+	if !reflect.DeepEqual(report.ManualChecks, wantManual) {
+		t.Errorf("manual checks = %+v, want %+v", report.ManualChecks, wantManual)
+	}
+}
+
+func TestParseAccessibilityReport_WithoutAccessibilityAudits(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseAccessibilityReport(&LabData{ManualAuditIDs: []string{"manual-audit"}}); err == nil {
+		t.Fatal("ParseAccessibilityReport returned nil error")
+	}
+}
+
+func TestCompareCriterionIDs_OrdersNumerically(t *testing.T) {
+	t.Parallel()
+
+	ids := []string{"1.4.10", "2.1.1", "1.4.3", "1.1.1"}
+	for i := 0; i < len(ids); i++ {
+		for j := i + 1; j < len(ids); j++ {
+			if compareCriterionIDs(ids[j], ids[i]) < 0 {
+				ids[i], ids[j] = ids[j], ids[i]
+			}
+		}
+	}
+	if want := []string{"1.1.1", "1.4.3", "1.4.10", "2.1.1"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("order = %v, want %v", ids, want)
+	}
+}
+
+func TestDiffAccessibilityReports_ClassifiesChanges(t *testing.T) {
+	t.Parallel()
+
+	baselineScore, currentScore := 0.72, 0.85
+	contrast := func(selectors ...string) AccessibilityAudit {
+		audit := AccessibilityAudit{ID: "color-contrast", Criteria: []string{"1.4.3"}}
+		for _, selector := range selectors {
+			audit.Nodes = append(audit.Nodes, FailingNode{Selector: selector})
+		}
+		return audit
+	}
+	baseline := &AccessibilityReport{
+		Score:        &baselineScore,
+		FailingNodes: 3,
+		Criteria: []CriterionFailures{
+			{WCAGCriterion: wcagCriteria["1.1.1"], Audits: []AccessibilityAudit{{ID: "image-alt", Nodes: []FailingNode{{Selector: "img"}}}}},
+			{WCAGCriterion: wcagCriteria["1.4.3"], Audits: []AccessibilityAudit{contrast("p.legal", "a.muted")}},
+		},
+	}
+	current := &AccessibilityReport{
+		Score:         &currentScore,
+		FailingNodes:  3,
+		Criteria:      []CriterionFailures{{WCAGCriterion: wcagCriteria["1.4.3"], Audits: []AccessibilityAudit{contrast("a.muted", "span.badge")}}},
+		BestPractices: []AccessibilityAudit{{ID: "heading-order", Nodes: []FailingNode{{Selector: "h4"}}}},
+	}
+
+	diff := DiffAccessibilityReports(baseline, current)
+	if diff.ScoreChange == nil || *diff.ScoreChange != 0.13 || diff.FailingNodesChange != 0 {
+		t.Errorf("diff totals = %v, %d", diff.ScoreChange, diff.FailingNodesChange)
+	}
+	if len(diff.Fixed) != 1 || diff.Fixed[0].ID != "image-alt" || !reflect.DeepEqual(diff.Fixed[0].RemovedNodes, []string{"img"}) {
+		t.Errorf("fixed = %+v", diff.Fixed)
+	}
+	if len(diff.Regressed) != 1 || diff.Regressed[0].ID != "heading-order" {
+		t.Errorf("regressed = %+v", diff.Regressed)
+	}
+	if len(diff.Changed) != 1 || !reflect.DeepEqual(diff.Changed[0].AddedNodes, []string{"span.badge"}) ||
+		!reflect.DeepEqual(diff.Changed[0].RemovedNodes, []string{"p.legal"}) {
+		t.Errorf("changed = %+v", diff.Changed)
+	}
+}
@@ -0,0 +1,167 @@
+package pagespeed
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+)
+
+// lcpElementAuditIDs lists the audits that identify the LCP element, in order
+// of preference. largest-contentful-paint-element predates Lighthouse 13.
+var lcpElementAuditIDs = []string{
+	lcpBreakdownInsightID,
+	"lcp-discovery-insight",
+	"largest-contentful-paint-element",
+}
+
+// layoutShiftAuditIDs lists the audits that attribute layout shifts, in order
+// of preference. layout-shifts predates Lighthouse 13.
+var layoutShiftAuditIDs = []string{
+	"cls-culprits-insight",
+	"layout-shifts",
+}
+
+// snippetSourcePattern matches the src attribute of an element snippet.
+var snippetSourcePattern = regexp.MustCompile(`\ssrc="([^"]+)"`)
+
+// LCPElement identifies the Largest Contentful Paint element.
+type LCPElement struct {
+	// Element is the LCP DOM element.
+	Element NodeValue `json:"element"`
+	// ResourceURL is the image URL from the element's src attribute, when it has one.
+	ResourceURL string `json:"resourceUrl,omitempty"`
+	// Source is the audit the element was read from.
+	Source string `json:"source"`
+}
+
+// LayoutShiftCulprit identifies an element that shifted during load.
+type LayoutShiftCulprit struct {
+	// Element is the shifted DOM element.
+	Element NodeValue `json:"element"`
+	// Score is the element's layout shift score when Lighthouse reports one.
+	Score *float64 `json:"score,omitempty"`
+	// Causes lists the likely root causes, such as an unsized image or web font.
+	Causes []string `json:"causes,omitempty"`
+	// Source is the audit the culprit was read from.
+	Source string `json:"source"`
+}
+
+// parseLCPElement returns the first LCP element node found in the LCP audits.
+func parseLCPElement(audits map[string]*rawAudit, finalURL string) *LCPElement {
+	for _, id := range lcpElementAuditIDs {
+		audit := audits[id]
+		if audit == nil {
+			continue
+		}
+		details, err := DecodeDetails(audit.Details)
+		if err != nil {
+			continue
+		}
+		node := findNode(details)
+		if node == nil {
+			continue
+		}
+		return &LCPElement{
+			Element:     *node,
+			ResourceURL: snippetResourceURL(node.Snippet, finalURL),
+			Source:      id,
+		}
+	}
+	return nil
+}
+
+func findNode(details AuditDetails) *NodeValue {
+	switch details := details.(type) {
+	case *NodeValue:
+		return details
+	case *ListDetails:
+		for _, item := range details.Items {
+			if node := findNode(item); node != nil {
+				return node
+			}
+		}
+	case *ListSectionDetails:
+		return findNode(details.Value)
+	case *TableDetails:
+		for _, item := range details.Items {
+			if node, ok := item.Node("node"); ok {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+func snippetResourceURL(snippet, base string) string {
+	match := snippetSourcePattern.FindStringSubmatch(snippet)
+	if match == nil {
+		return ""
+	}
+	reference, err := url.Parse(match[1])
+	if err != nil {
+		return ""
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil || base == "" {
+		return reference.String()
+	}
+	return baseURL.ResolveReference(reference).String()
+}
+
+// parseLayoutShiftCulprits returns the shifted elements from the first layout
+// shift audit that has any, highest score first.
+func parseLayoutShiftCulprits(audits map[string]*rawAudit) []LayoutShiftCulprit {
+	for _, id := range layoutShiftAuditIDs {
+		audit := audits[id]
+		if audit == nil {
+			continue
+		}
+		details, err := DecodeDetails(audit.Details)
+		if err != nil {
+			continue
+		}
+		culprits := make([]LayoutShiftCulprit, 0)
+		collectLayoutShifts(details, id, &culprits)
+		if len(culprits) == 0 {
+			continue
+		}
+		sort.SliceStable(culprits, func(i, j int) bool {
+			return scoreValue(culprits[i].Score) > scoreValue(culprits[j].Score)
+		})
+		return culprits
+	}
+	return nil
+}
+
+func collectLayoutShifts(details AuditDetails, source string, culprits *[]LayoutShiftCulprit) {
+	switch details := details.(type) {
+	case *ListDetails:
+		for _, item := range details.Items {
+			collectLayoutShifts(item, source, culprits)
+		}
+	case *TableDetails:
+		for _, item := range details.Items {
+			node, ok := item.Node("node")
+			if !ok {
+				continue
+			}
+			culprit := LayoutShiftCulprit{Element: *node, Source: source}
+			if score, ok := item.Number("score"); ok {
+				culprit.Score = &score
+			}
+			for _, subItem := range item.SubItems() {
+				if cause, ok := subItem.Text("cause"); ok && cause != "" {
+					culprit.Causes = append(culprit.Causes, cause)
+				}
+			}
+			*culprits = append(*culprits, culprit)
+		}
+	}
+}
+
+func scoreValue(score *float64) float64 {
+	if score == nil {
+		return 0
+	}
+	return *score
+}
@@ -0,0 +1,83 @@
+package pagespeed
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"testing"
+)
+
+func TestParseLCPElement_ReadsNodeAndResource(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	lcp := result.LabData.LCPElement
+	if lcp == nil {
+		t.Fatal("LCP element must be extracted")
+	}
+	if lcp.Source != lcpBreakdownInsightID || lcp.Element.Selector != "main > img.hero" ||
+		lcp.Element.NodeLabel != "Hero image" || lcp.Element.BoundingRect == nil ||
+		lcp.Element.BoundingRect.Width != 30 {
+		t.Errorf("LCP element = %+v", lcp)
+	}
+	if lcp.ResourceURL != "https://example.test/hero.jpg" {
+		t.Errorf("resource URL = %q, want the resolved src", lcp.ResourceURL)
+	}
+	if got := snippetResourceURL(`<div class="hero">`, "https://example.test/"); got != "" {
+		t.Errorf("resource URL without src = %q, want empty", got)
+	}
+}
+
+func TestParseLayoutShiftCulprits_SortsByScoreWithCauses(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	culprits := result.LabData.LayoutShiftCulprits
+	if len(culprits) != 2 {
+		t.Fatalf("culprits = %+v, want two elements without the total row", culprits)
+	}
+	first := culprits[0]
+	if first.Element.Selector != "body > div.ad-slot" || first.Score == nil || *first.Score != 0.06 ||
+		len(first.Causes) != 2 || first.Causes[1] != "Web font" || first.Source != "cls-culprits-insight" {
+		t.Errorf("first culprit = %+v", first)
+	}
+	if culprits[1].Causes[0] != "Unsized image element" {
+		t.Errorf("second culprit = %+v", culprits[1])
+	}
+}
+
+func TestCropElement_CropsFullPageScreenshot(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	screenshots := result.LabData.Screenshots
+	crop, err := screenshots.CropElement(result.LabData.LCPElement.Element)
+	if err != nil {
+		t.Fatalf("CropElement: %v", err)
+	}
+	decoded, err := jpeg.Decode(bytes.NewReader(crop.Data))
+	if err != nil {
+		t.Fatalf("decode crop: %v", err)
+	}
+	if decoded.Bounds() != image.Rect(0, 0, 30, 20) {
+		t.Errorf("crop bounds = %v, want 30x20", decoded.Bounds())
+	}
+	if red, green, blue, _ := decoded.At(15, 10).RGBA(); blue>>8 < 150 || red>>8 > 100 || green>>8 > 150 {
+		t.Errorf("crop center = %d,%d,%d; want the blue hero block", red>>8, green>>8, blue>>8)
+	}
+
+	// Undecodable full-page images fall back to the viewport screenshot.
+	screenshots.FullPage.Data = []byte("RIFF....WEBP")
+	if _, err := screenshots.CropElement(NodeValue{
+		Selector:     "main > img.hero",
+		BoundingRect: &Rect{Top: 1, Bottom: 5, Left: 1, Right: 5, Width: 4, Height: 4},
+	}); err != nil {
+		t.Errorf("viewport crop: %v", err)
+	}
+	if _, err := screenshots.CropElement(NodeValue{
+		Selector:     "footer",
+		BoundingRect: &Rect{Top: 500, Bottom: 520, Left: 0, Right: 10, Width: 10, Height: 20},
+	}); err == nil {
+		t.Error("an element below the viewport must fail without a decodable full-page image")
+	}
+}
@@ -0,0 +1,103 @@
+package pagespeed
+
+import (
+	"sort"
+	"strings"
+)
+
+// CategoryBreakdown explains a category score from its weighted audits.
+type CategoryBreakdown struct {
+	// TotalWeight is the sum of the weights of the scored audits.
+	TotalWeight float64 `json:"totalWeight"`
+	// ComputedScore is the weighted mean of the audit scores, out of 100.
+	ComputedScore float64 `json:"computedScore"`
+	// PointsLost is 100 minus ComputedScore.
+	PointsLost float64 `json:"pointsLost"`
+	// Audits contains the weighted audits, most points lost first.
+	Audits []AuditContribution `json:"audits"`
+}
+
+// AuditContribution contains one weighted audit's share of a category score.
+type AuditContribution struct {
+	// ID is the Lighthouse audit identifier.
+	ID string `json:"id"`
+	// Title is the human-readable audit title.
+	Title string `json:"title,omitempty"`
+	// Group is the category group the audit belongs to, such as metrics.
+	Group string `json:"group,omitempty"`
+	// Weight is the auditRef weight reported by Lighthouse.
+	Weight float64 `json:"weight"`
+	// Score is the audit score; a missing score counts as zero.
+	Score *float64 `json:"score,omitempty"`
+	// MaxPoints is the audit's share of the category, in points out of 100.
+	MaxPoints float64 `json:"maxPoints"`
+	// Contribution is the points the audit earned.
+	Contribution float64 `json:"contribution"`
+	// PointsLost is MaxPoints minus Contribution.
+	PointsLost float64 `json:"pointsLost"`
+}
+
+// breakdownCategory distributes a category's 100 points over its weighted
+// audits. Not applicable and manual audits carry no weight, as in Lighthouse.
+// It returns nil when no audit is weighted.
+func breakdownCategory(category *rawCategory, audits map[string]*rawAudit) *CategoryBreakdown {
+	type weightedRef struct {
+		ref   rawAuditRef
+		audit *rawAudit
+	}
+	refs := make([]weightedRef, 0, len(category.AuditRefs))
+	var totalWeight float64
+	for _, ref := range category.AuditRefs {
+		if ref.Weight <= 0 {
+			continue
+		}
+		audit := audits[ref.ID]
+		if audit != nil {
+			switch strings.ToLower(audit.ScoreDisplayMode) {
+			case "notapplicable", "manual":
+				continue
+			}
+		}
+		refs = append(refs, weightedRef{ref: ref, audit: audit})
+		totalWeight += ref.Weight
+	}
+	if totalWeight == 0 {
+		return nil
+	}
+
+	breakdown := &CategoryBreakdown{
+		TotalWeight: totalWeight,
+		Audits:      make([]AuditContribution, 0, len(refs)),
+	}
+	var earned float64
+	for _, weighted := range refs {
+		entry := AuditContribution{
+			ID:        weighted.ref.ID,
+			Group:     weighted.ref.Group,
+			Weight:    weighted.ref.Weight,
+			MaxPoints: weighted.ref.Weight / totalWeight * 100,
+		}
+		if weighted.audit != nil {
+			entry.Title = weighted.audit.Title
+			entry.Score = weighted.audit.Score
+		}
+		if entry.Score != nil {
+			entry.Contribution = *entry.Score * entry.MaxPoints
+		}
+		earned += entry.Contribution
+		entry.PointsLost = roundScore(entry.MaxPoints - entry.Contribution)
+		entry.MaxPoints = roundScore(entry.MaxPoints)
+		entry.Contribution = roundScore(entry.Contribution)
+		breakdown.Audits = append(breakdown.Audits, entry)
+	}
+	breakdown.ComputedScore = roundScore(earned)
+	breakdown.PointsLost = roundScore(100 - earned)
+
+	sort.SliceStable(breakdown.Audits, func(i, j int) bool {
+		if breakdown.Audits[i].PointsLost != breakdown.Audits[j].PointsLost {
+			return breakdown.Audits[i].PointsLost > breakdown.Audits[j].PointsLost
+		}
+		return breakdown.Audits[i].ID < breakdown.Audits[j].ID
+	})
+	return breakdown
+}
@@ -0,0 +1,59 @@
+package pagespeed
+
+import "testing"
+
+func TestBreakdownCategory_AttributesFixtureScore(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	breakdown := result.LabData.Categories["performance"].Breakdown
+	if breakdown == nil {
+		t.Fatal("performance breakdown = nil")
+	}
+	if breakdown.TotalWeight != 0.35 || len(breakdown.Audits) != 2 {
+		t.Fatalf("breakdown = %+v, want the two weighted metrics", breakdown)
+	}
+	lcp := breakdown.Audits[0]
+	if lcp.ID != "largest-contentful-paint" || lcp.Group != "metrics" ||
+		lcp.MaxPoints != 71.43 || lcp.Contribution != 39.29 || lcp.PointsLost != 32.14 {
+		t.Errorf("first audit = %+v, want LCP losing the most points", lcp)
+	}
+	if breakdown.ComputedScore != 64.43 || breakdown.PointsLost != 35.57 {
+		t.Errorf("computed = %v, lost = %v; want 64.43 and 35.57", breakdown.ComputedScore, breakdown.PointsLost)
+	}
+}
+
+func TestBreakdownCategory_SkipsUnscorableAudits(t *testing.T) {
+	t.Parallel()
+
+	one, zero := 1.0, 0.0
+	category := &rawCategory{AuditRefs: []rawAuditRef{
+		{ID: "color-contrast", Weight: 7, Group: "a11y-color-contrast"},
+		{ID: "image-alt", Weight: 10, Group: "a11y-names-labels"},
+		{ID: "video-caption", Weight: 10, Group: "a11y-audio-video"},
+		{ID: "focus-traps", Weight: 0},
+		{ID: "errored-audit", Weight: 3},
+	}}
+	audits := map[string]*rawAudit{
+		"color-contrast": {Title: "Contrast", Score: &zero, ScoreDisplayMode: "binary"},
+		"image-alt":      {Title: "Alt text", Score: &one, ScoreDisplayMode: "binary"},
+		"video-caption":  {ScoreDisplayMode: "notApplicable"},
+		"errored-audit":  {ScoreDisplayMode: "error"},
+	}
+	breakdown := breakdownCategory(category, audits)
+	if breakdown.TotalWeight != 20 || len(breakdown.Audits) != 3 {
+		t.Fatalf("breakdown = %+v, want three scorable audits", breakdown)
+	}
+	if first := breakdown.Audits[0]; first.ID != "color-contrast" || first.PointsLost != 35 {
+		t.Errorf("first audit = %+v, want color-contrast losing 35 points", first)
+	}
+	if errored := breakdown.Audits[1]; errored.ID != "errored-audit" || errored.PointsLost != 15 {
+		t.Errorf("second audit = %+v, want errored-audit counted as zero", errored)
+	}
+	if breakdown.ComputedScore != 50 {
+		t.Errorf("computed score = %v, want 50", breakdown.ComputedScore)
+	}
+	if breakdownCategory(&rawCategory{}, audits) != nil {
+		t.Error("unweighted category breakdown must be nil")
+	}
+}
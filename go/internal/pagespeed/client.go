@@ -113,15 +113,17 @@ func (c *Client) Analyze(ctx context.Context, analysisRequest AnalysisRequest) (
 	}
 
 	if response.StatusCode != http.StatusOK {
-		return nil, &apihttp.StatusError{
-			Service:     "PSI API",
-			StatusCode:  response.StatusCode,
-			BodySnippet: truncate(string(response.Body), 300),
-		}
+		return nil, apihttp.NewStatusError("PSI API", response, 300)
 	}
 
+	return ParseResponse(analysisRequest, response.Body)
+}
+
+// ParseResponse decodes a PageSpeed Insights API response body for the
+// request that produced it.
+func ParseResponse(analysisRequest AnalysisRequest, body []byte) (*AnalysisResult, error) {
 	var raw apiResponse
-	if err := json.Unmarshal(response.Body, &raw); err != nil {
+	if err := json.Unmarshal(body, &raw); err != nil {
 		return nil, fmt.Errorf("parsing PSI response: %w", err)
 	}
 
@@ -170,10 +172,3 @@ func normalizeCategories(categories []string) ([]string, error) {
 	}
 	return normalized, nil
 }
-
-func truncate(s string, max int) string {
-	if len(s) <= max {
-		return s
-	}
-	return s[:max] + "..."
-}
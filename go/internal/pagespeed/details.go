@@ -0,0 +1,644 @@
+package pagespeed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Lighthouse audit details types.
+const (
+	DetailsTypeOpportunity          = "opportunity"
+	DetailsTypeTable                = "table"
+	DetailsTypeList                 = "list"
+	DetailsTypeListSection          = "list-section"
+	DetailsTypeChecklist            = "checklist"
+	DetailsTypeCriticalRequestChain = "criticalrequestchain"
+	DetailsTypeNetworkTree          = "network-tree"
+	DetailsTypeTreemapData          = "treemap-data"
+	DetailsTypeFilmstrip            = "filmstrip"
+	DetailsTypeScreenshot           = "screenshot"
+	DetailsTypeDebugData            = "debugdata"
+	DetailsTypeNode                 = "node"
+	DetailsTypeSourceLocation       = "source-location"
+	DetailsTypeURL                  = "url"
+	DetailsTypeText                 = "text"
+	DetailsTypeCode                 = "code"
+)
+
+// Lighthouse table column value types.
+const (
+	ValueTypeBytes          = "bytes"
+	ValueTypeMs             = "ms"
+	ValueTypeTimespanMs     = "timespanMs"
+	ValueTypeNumeric        = "numeric"
+	ValueTypeText           = "text"
+	ValueTypeCode           = "code"
+	ValueTypeURL            = "url"
+	ValueTypeNode           = "node"
+	ValueTypeSourceLocation = "source-location"
+	ValueTypeThumbnail      = "thumbnail"
+	ValueTypeLink           = "link"
+)
+
+// AuditDetails is a decoded Lighthouse audit details object or details item.
+type AuditDetails interface {
+	// DetailsType returns the Lighthouse details type, such as table.
+	DetailsType() string
+}
+
+// DecodeDetails decodes Lighthouse audit details into their typed model.
+// Unrecognized types decode to UnknownDetails; empty details decode to nil.
+func DecodeDetails(raw json.RawMessage) (AuditDetails, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("decode audit details: %w", err)
+	}
+
+	var details AuditDetails
+	switch header.Type {
+	case DetailsTypeOpportunity:
+		details = &OpportunityDetails{}
+	case DetailsTypeTable:
+		details = &TableDetails{}
+	case DetailsTypeList:
+		details = &ListDetails{}
+	case DetailsTypeListSection:
+		details = &ListSectionDetails{}
+	case DetailsTypeChecklist:
+		details = &ChecklistDetails{}
+	case DetailsTypeCriticalRequestChain:
+		details = &CriticalRequestChainDetails{}
+	case DetailsTypeNetworkTree:
+		details = &NetworkTreeDetails{}
+	case DetailsTypeTreemapData:
+		details = &TreemapDataDetails{}
+	case DetailsTypeFilmstrip:
+		details = &FilmstripDetails{}
+	case DetailsTypeScreenshot:
+		details = &ScreenshotDetails{}
+	case DetailsTypeDebugData:
+		details = &DebugDataDetails{}
+	case DetailsTypeNode:
+		details = &NodeValue{}
+	case DetailsTypeSourceLocation:
+		details = &SourceLocationValue{}
+	case DetailsTypeURL:
+		details = &URLValue{}
+	case DetailsTypeText, DetailsTypeCode:
+		details = &TextValue{}
+	default:
+		return &UnknownDetails{Type: header.Type, Raw: append(json.RawMessage(nil), raw...)}, nil
+	}
+	if err := json.Unmarshal(raw, details); err != nil {
+		return nil, fmt.Errorf("decode %s audit details: %w", header.Type, err)
+	}
+	return details, nil
+}
+
+// DecodeDetails decodes the audit's details into their typed model.
+func (a LighthouseAudit) DecodeDetails() (AuditDetails, error) {
+	return DecodeDetails(a.Details)
+}
+
+// UnknownDetails preserves a details type this package does not model.
+type UnknownDetails struct {
+	// Type is the Lighthouse details type.
+	Type string `json:"type"`
+	// Raw is the undecoded details object.
+	Raw json.RawMessage `json:"raw"`
+}
+
+// DetailsType implements AuditDetails.
+func (d *UnknownDetails) DetailsType() string { return d.Type }
+
+// TableHeading describes one table or opportunity column.
+type TableHeading struct {
+	// Key is the item property rendered in the column; it is empty for
+	// columns that only render sub-items.
+	Key string `json:"key"`
+	// Label is the human-readable column label.
+	Label string `json:"label,omitempty"`
+	// ValueType identifies how the column values are rendered, such as bytes or ms.
+	ValueType string `json:"valueType,omitempty"`
+	// DisplayUnit is the preferred unit for rendering, such as kb.
+	DisplayUnit string `json:"displayUnit,omitempty"`
+	// Granularity is the rendering precision of numeric values.
+	Granularity float64 `json:"granularity,omitempty"`
+	// SubItemsHeading describes the column's sub-item values.
+	SubItemsHeading *SubItemsHeading `json:"subItemsHeading,omitempty"`
+}
+
+// SubItemsHeading describes how sub-item values render within a column.
+type SubItemsHeading struct {
+	// Key is the sub-item property rendered in the column.
+	Key string `json:"key"`
+	// ValueType overrides the column value type for sub-items.
+	ValueType string `json:"valueType,omitempty"`
+	// DisplayUnit is the preferred unit for rendering.
+	DisplayUnit string `json:"displayUnit,omitempty"`
+	// Granularity is the rendering precision of numeric values.
+	Granularity float64 `json:"granularity,omitempty"`
+}
+
+// TableItem is one table row. Values stay raw because their shape depends
+// on the column value type; the accessor methods decode them.
+type TableItem map[string]json.RawMessage
+
+// Number returns a numeric value, including numeric strings and
+// {type: numeric} objects.
+func (i TableItem) Number(key string) (float64, bool) {
+	raw, ok := i[key]
+	if !ok {
+		return 0, false
+	}
+	var number float64
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return number, true
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		number, err := strconv.ParseFloat(text, 64)
+		return number, err == nil
+	}
+	var value struct {
+		Value *float64 `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &value); err == nil && value.Value != nil {
+		return *value.Value, true
+	}
+	return 0, false
+}
+
+// Text returns a string value, including the value of url, text, and code
+// objects.
+func (i TableItem) Text(key string) (string, bool) {
+	raw, ok := i[key]
+	if !ok {
+		return "", false
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, true
+	}
+	var value struct {
+		Value *string `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &value); err == nil && value.Value != nil {
+		return *value.Value, true
+	}
+	return "", false
+}
+
+// Node returns a node value.
+func (i TableItem) Node(key string) (*NodeValue, bool) {
+	var node NodeValue
+	if !i.decodeTyped(key, DetailsTypeNode, &node) {
+		return nil, false
+	}
+	return &node, true
+}
+
+// SourceLocation returns a source-location value.
+func (i TableItem) SourceLocation(key string) (*SourceLocationValue, bool) {
+	var location SourceLocationValue
+	if !i.decodeTyped(key, DetailsTypeSourceLocation, &location) {
+		return nil, false
+	}
+	return &location, true
+}
+
+// SubItems returns the row's nested sub-item rows.
+func (i TableItem) SubItems() []TableItem {
+	var subItems struct {
+		Items []TableItem `json:"items"`
+	}
+	if raw, ok := i["subItems"]; !ok || json.Unmarshal(raw, &subItems) != nil {
+		return nil
+	}
+	return subItems.Items
+}
+
+func (i TableItem) decodeTyped(key, detailsType string, target any) bool {
+	raw, ok := i[key]
+	if !ok {
+		return false
+	}
+	var header struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(raw, &header) != nil || header.Type != detailsType {
+		return false
+	}
+	return json.Unmarshal(raw, target) == nil
+}
+
+// TableDetails is a Lighthouse table.
+type TableDetails struct {
+	// Headings contains the column definitions.
+	Headings []TableHeading `json:"headings"`
+	// Items contains the table rows.
+	Items []TableItem `json:"items"`
+	// Summary contains the table's total savings.
+	Summary *TableSummary `json:"summary,omitempty"`
+	// SortedBy lists the keys the rows are sorted by.
+	SortedBy []string `json:"sortedBy,omitempty"`
+	// IsEntityGrouped reports whether rows are grouped by entity.
+	IsEntityGrouped bool `json:"isEntityGrouped,omitempty"`
+	// DebugData contains additional machine-readable data.
+	DebugData *DebugDataDetails `json:"debugData,omitempty"`
+}
+
+// DetailsType implements AuditDetails.
+func (*TableDetails) DetailsType() string { return DetailsTypeTable }
+
+// ValueType returns the value type of the column rendering key.
+func (t *TableDetails) ValueType(key string) string {
+	return headingValueType(t.Headings, key)
+}
+
+// TableSummary contains the total savings of a table.
+type TableSummary struct {
+	// WastedMs is the estimated time savings in milliseconds.
+	WastedMs *float64 `json:"wastedMs,omitempty"`
+	// WastedBytes is the estimated transfer savings in bytes.
+	WastedBytes *float64 `json:"wastedBytes,omitempty"`
+}
+
+// OpportunityDetails is a Lighthouse opportunity table with overall savings.
+type OpportunityDetails struct {
+	// Headings contains the column definitions.
+	Headings []TableHeading `json:"headings"`
+	// Items contains the opportunity rows.
+	Items []TableItem `json:"items"`
+	// OverallSavingsMs is the estimated load time savings in milliseconds.
+	OverallSavingsMs float64 `json:"overallSavingsMs"`
+	// OverallSavingsBytes is the estimated transfer savings in bytes.
+	OverallSavingsBytes *float64 `json:"overallSavingsBytes,omitempty"`
+	// SortedBy lists the keys the rows are sorted by.
+	SortedBy []string `json:"sortedBy,omitempty"`
+	// IsEntityGrouped reports whether rows are grouped by entity.
+	IsEntityGrouped bool `json:"isEntityGrouped,omitempty"`
+	// DebugData contains additional machine-readable data.
+	DebugData *DebugDataDetails `json:"debugData,omitempty"`
+}
+
+// DetailsType implements AuditDetails.
+func (*OpportunityDetails) DetailsType() string { return DetailsTypeOpportunity }
+
+// ValueType returns the value type of the column rendering key.
+func (o *OpportunityDetails) ValueType(key string) string {
+	return headingValueType(o.Headings, key)
+}
+
+func headingValueType(headings []TableHeading, key string) string {
+	for _, heading := range headings {
+		if heading.Key == key {
+			return heading.ValueType
+		}
+	}
+	return ""
+}
+
+// ListDetails contains nested details items, such as a table followed by a node.
+type ListDetails struct {
+	// Items contains the decoded list items.
+	Items []AuditDetails `json:"items"`
+}
+
+// DetailsType implements AuditDetails.
+func (*ListDetails) DetailsType() string { return DetailsTypeList }
+
+// UnmarshalJSON decodes each list item by its details type. An item that
+// fails to decode is kept as UnknownDetails so the other items remain usable.
+func (l *ListDetails) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	l.Items = make([]AuditDetails, 0, len(raw.Items))
+	for _, item := range raw.Items {
+		details, err := DecodeDetails(item)
+		if err != nil {
+			var header struct {
+				Type string `json:"type"`
+			}
+			_ = json.Unmarshal(item, &header)
+			details = &UnknownDetails{Type: header.Type, Raw: append(json.RawMessage(nil), item...)}
+		}
+		if details != nil {
+			l.Items = append(l.Items, details)
+		}
+	}
+	return nil
+}
+
+// ListSectionDetails is a titled list item wrapping another details value.
+type ListSectionDetails struct {
+	// Title is the section title.
+	Title string `json:"title,omitempty"`
+	// Description explains the section.
+	Description string `json:"description,omitempty"`
+	// Value is the decoded section content.
+	Value AuditDetails `json:"value"`
+}
+
+// DetailsType implements AuditDetails.
+func (*ListSectionDetails) DetailsType() string { return DetailsTypeListSection }
+
+// UnmarshalJSON decodes the section value by its details type.
+func (s *ListSectionDetails) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Title       string          `json:"title"`
+		Description string          `json:"description"`
+		Value       json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	value, err := DecodeDetails(raw.Value)
+	if err != nil {
+		return err
+	}
+	*s = ListSectionDetails{Title: raw.Title, Description: raw.Description, Value: value}
+	return nil
+}
+
+// ChecklistDetails contains pass or fail checks keyed by check identifier.
+type ChecklistDetails struct {
+	// Items contains the checks.
+	Items map[string]ChecklistItem `json:"items"`
+	// DebugData contains additional machine-readable data.
+	DebugData *DebugDataDetails `json:"debugData,omitempty"`
+}
+
+// DetailsType implements AuditDetails.
+func (*ChecklistDetails) DetailsType() string { return DetailsTypeChecklist }
+
+// ChecklistItem is one check.
+type ChecklistItem struct {
+	// Label is the human-readable check description.
+	Label string `json:"label"`
+	// Value reports whether the check passed.
+	Value bool `json:"value"`
+}
+
+// CriticalRequestChainDetails contains the critical request chains keyed by
+// request identifier.
+type CriticalRequestChainDetails struct {
+	// Chains contains the chain roots.
+	Chains map[string]CriticalRequestNode `json:"chains"`
+	// LongestChain summarizes the longest chain.
+	LongestChain CriticalRequestChainSummary `json:"longestChain"`
+}
+
+// DetailsType implements AuditDetails.
+func (*CriticalRequestChainDetails) DetailsType() string {
+	return DetailsTypeCriticalRequestChain
+}
+
+// CriticalRequestNode is one request in a critical request chain.
+type CriticalRequestNode struct {
+	// Request describes the request.
+	Request CriticalRequest `json:"request"`
+	// Children contains the requests initiated by this one.
+	Children map[string]CriticalRequestNode `json:"children,omitempty"`
+}
+
+// CriticalRequest describes a request in a critical request chain. Times are
+// in seconds on the trace clock.
+type CriticalRequest struct {
+	// URL is the request URL.
+	URL string `json:"url"`
+	// StartTime is when the request started.
+	StartTime float64 `json:"startTime"`
+	// EndTime is when the response finished.
+	EndTime float64 `json:"endTime"`
+	// ResponseReceivedTime is when the response headers arrived.
+	ResponseReceivedTime float64 `json:"responseReceivedTime"`
+	// TransferSize is the transfer size in bytes.
+	TransferSize float64 `json:"transferSize"`
+}
+
+// CriticalRequestChainSummary summarizes the longest critical request chain.
+type CriticalRequestChainSummary struct {
+	// Duration is the chain duration in milliseconds.
+	Duration float64 `json:"duration"`
+	// Length is the number of requests in the chain.
+	Length int `json:"length"`
+	// TransferSize is the chain's total transfer size in bytes.
+	TransferSize float64 `json:"transferSize"`
+}
+
+// NetworkTreeDetails contains the Lighthouse 13 network dependency tree
+// keyed by request identifier.
+type NetworkTreeDetails struct {
+	// Chains contains the tree roots.
+	Chains map[string]NetworkTreeNode `json:"chains"`
+	// LongestChain summarizes the longest chain.
+	LongestChain NetworkTreeSummary `json:"longestChain"`
+}
+
+// DetailsType implements AuditDetails.
+func (*NetworkTreeDetails) DetailsType() string { return DetailsTypeNetworkTree }
+
+// NetworkTreeNode is one request in the network dependency tree.
+type NetworkTreeNode struct {
+	// URL is the request URL.
+	URL string `json:"url"`
+	// NavStartToEndTime is the request end time in milliseconds after navigation start.
+	NavStartToEndTime float64 `json:"navStartToEndTime"`
+	// TransferSize is the request transfer size in bytes.
+	TransferSize float64 `json:"transferSize"`
+	// IsLongest reports whether the request is on the longest chain.
+	IsLongest bool `json:"isLongest,omitempty"`
+	// Children contains the requests initiated by this one.
+	Children map[string]NetworkTreeNode `json:"children,omitempty"`
+}
+
+// NetworkTreeSummary summarizes the longest network dependency chain.
+type NetworkTreeSummary struct {
+	// Duration is the chain duration in milliseconds.
+	Duration float64 `json:"duration"`
+}
+
+// TreemapDataDetails contains the script treemap.
+type TreemapDataDetails struct {
+	// Nodes contains one root node per script.
+	Nodes []TreemapNode `json:"nodes"`
+}
+
+// DetailsType implements AuditDetails.
+func (*TreemapDataDetails) DetailsType() string { return DetailsTypeTreemapData }
+
+// TreemapNode is a script, bundle directory, or module in the script treemap.
+type TreemapNode struct {
+	// Name is the script URL or module path segment.
+	Name string `json:"name"`
+	// ResourceBytes is the uncompressed size in bytes.
+	ResourceBytes float64 `json:"resourceBytes"`
+	// EncodedBytes is the transfer size in bytes; it is set on root nodes.
+	EncodedBytes *float64 `json:"encodedBytes,omitempty"`
+	// UnusedBytes is the size of code not executed during load.
+	UnusedBytes *float64 `json:"unusedBytes,omitempty"`
+	// DuplicatedNormalizedModuleName names a module duplicated across bundles.
+	DuplicatedNormalizedModuleName string `json:"duplicatedNormalizedModuleName,omitempty"`
+	// Children contains nested nodes.
+	Children []TreemapNode `json:"children,omitempty"`
+}
+
+// FilmstripDetails contains the screenshot thumbnails captured during load.
+type FilmstripDetails struct {
+	// Scale is the timeline length in milliseconds.
+	Scale float64 `json:"scale"`
+	// Items contains the frames in capture order.
+	Items []FilmstripFrame `json:"items"`
+}
+
+// DetailsType implements AuditDetails.
+func (*FilmstripDetails) DetailsType() string { return DetailsTypeFilmstrip }
+
+// FilmstripFrame is one screenshot thumbnail.
+type FilmstripFrame struct {
+	// Timing is the capture time in milliseconds after navigation start.
+	Timing float64 `json:"timing"`
+	// Timestamp is the capture trace timestamp in microseconds.
+	Timestamp float64 `json:"timestamp"`
+	// Data is the image as a base64 data URL.
+	Data string `json:"data"`
+}
+
+// ScreenshotDetails contains a single screenshot.
+type ScreenshotDetails struct {
+	// Timing is the capture time in milliseconds after navigation start.
+	Timing float64 `json:"timing"`
+	// Timestamp is the capture trace timestamp in microseconds.
+	Timestamp float64 `json:"timestamp"`
+	// Data is the image as a base64 data URL.
+	Data string `json:"data"`
+}
+
+// DetailsType implements AuditDetails.
+func (*ScreenshotDetails) DetailsType() string { return DetailsTypeScreenshot }
+
+// DebugDataDetails contains free-form machine-readable audit data.
+type DebugDataDetails struct {
+	// Items contains list-shaped debug data.
+	Items []map[string]any `json:"items,omitempty"`
+	// Fields contains the remaining debug data properties.
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// DetailsType implements AuditDetails.
+func (*DebugDataDetails) DetailsType() string { return DetailsTypeDebugData }
+
+// UnmarshalJSON separates items from the free-form debug properties.
+func (d *DebugDataDetails) UnmarshalJSON(data []byte) error {
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	var items struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	delete(fields, "type")
+	delete(fields, "items")
+	*d = DebugDataDetails{Items: items.Items}
+	if len(fields) > 0 {
+		d.Fields = fields
+	}
+	return nil
+}
+
+// NodeValue identifies a DOM element.
+type NodeValue struct {
+	// LHID is the Lighthouse element identifier used by the full-page screenshot.
+	LHID string `json:"lhId,omitempty"`
+	// Path is the DevTools node path.
+	Path string `json:"path,omitempty"`
+	// Selector is a CSS selector for the element.
+	Selector string `json:"selector,omitempty"`
+	// BoundingRect is the element's position in CSS pixels.
+	BoundingRect *Rect `json:"boundingRect,omitempty"`
+	// Snippet is the element's opening HTML tag.
+	Snippet string `json:"snippet,omitempty"`
+	// NodeLabel is a human-readable element description.
+	NodeLabel string `json:"nodeLabel,omitempty"`
+	// Explanation describes why the element failed an audit.
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// DetailsType implements AuditDetails.
+func (*NodeValue) DetailsType() string { return DetailsTypeNode }
+
+// Rect is an element rectangle in CSS pixels.
+type Rect struct {
+	// Top is the distance from the top of the page to the top edge.
+	Top float64 `json:"top"`
+	// Bottom is the distance from the top of the page to the bottom edge.
+	Bottom float64 `json:"bottom"`
+	// Left is the distance from the left of the page to the left edge.
+	Left float64 `json:"left"`
+	// Right is the distance from the left of the page to the right edge.
+	Right float64 `json:"right"`
+	// Width is the rectangle width.
+	Width float64 `json:"width"`
+	// Height is the rectangle height.
+	Height float64 `json:"height"`
+}
+
+// SourceLocationValue identifies a position in a script or stylesheet.
+type SourceLocationValue struct {
+	// URL is the resource URL.
+	URL string `json:"url"`
+	// URLProvider is network or comment.
+	URLProvider string `json:"urlProvider,omitempty"`
+	// Line is the zero-based line number.
+	Line int `json:"line"`
+	// Column is the zero-based column number.
+	Column int `json:"column"`
+	// Original is the source-mapped position when a source map is available.
+	Original *OriginalSourceLocation `json:"original,omitempty"`
+}
+
+// DetailsType implements AuditDetails.
+func (*SourceLocationValue) DetailsType() string { return DetailsTypeSourceLocation }
+
+// OriginalSourceLocation is a source-mapped position.
+type OriginalSourceLocation struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// URLValue is a URL rendered as a link.
+type URLValue struct {
+	// Value is the URL.
+	Value string `json:"value"`
+}
+
+// DetailsType implements AuditDetails.
+func (*URLValue) DetailsType() string { return DetailsTypeURL }
+
+// TextValue is a text or code value.
+type TextValue struct {
+	// Type is text or code.
+	Type string `json:"type"`
+	// Value is the text.
+	Value string `json:"value"`
+}
+
+// DetailsType implements AuditDetails.
+func (v *TextValue) DetailsType() string { return v.Type }
@@ -0,0 +1,172 @@
+package pagespeed
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decodeFixtureDetails(t *testing.T, id string) AuditDetails {
+	t.Helper()
+
+	audit, ok := loadPSIFixture(t).LighthouseResult.Audits[id]
+	if !ok {
+		t.Fatalf("fixture audit %s is missing", id)
+	}
+	details, err := DecodeDetails(audit.Details)
+	if err != nil {
+		t.Fatalf("DecodeDetails(%s): %v", id, err)
+	}
+	return details
+}
+
+func TestDecodeDetails_TablesAndValueTypes(t *testing.T) {
+	t.Parallel()
+
+	table, ok := decodeFixtureDetails(t, "network-requests").(*TableDetails)
+	if !ok {
+		t.Fatal("network-requests must decode to a table")
+	}
+	if len(table.Items) != 6 || table.ValueType("transferSize") != ValueTypeBytes ||
+		table.ValueType("networkEndTime") != ValueTypeMs {
+		t.Fatalf("table = %d items, headings %+v", len(table.Items), table.Headings)
+	}
+	if url, _ := table.Items[1].Text("url"); url != "https://example.test/styles.css" {
+		t.Errorf("second URL = %q", url)
+	}
+	if size, ok := table.Items[1].Number("transferSize"); !ok || size != 24000 {
+		t.Errorf("transfer size = %v, %v; want 24000", size, ok)
+	}
+	if table.DebugData == nil || table.DebugData.Fields["networkStartTimeTs"] == nil {
+		t.Errorf("debug data = %+v, want networkStartTimeTs", table.DebugData)
+	}
+
+	opportunity, ok := decodeFixtureDetails(t, "uses-text-compression").(*OpportunityDetails)
+	if !ok || opportunity.OverallSavingsBytes == nil || *opportunity.OverallSavingsBytes != 12288 ||
+		opportunity.ValueType("wastedBytes") != ValueTypeBytes {
+		t.Errorf("opportunity = %+v", opportunity)
+	}
+
+	errors, ok := decodeFixtureDetails(t, "errors-in-console").(*TableDetails)
+	if !ok {
+		t.Fatal("errors-in-console must decode to a table")
+	}
+	location, ok := errors.Items[0].SourceLocation("sourceLocation")
+	if !ok || location.URL != "https://example.test/app.js" || location.Line != 120 {
+		t.Errorf("source location = %+v, %v", location, ok)
+	}
+	if _, ok := errors.Items[0].Node("sourceLocation"); ok {
+		t.Error("a source location must not decode as a node")
+	}
+}
+
+func TestDecodeDetails_NestedListsAndTrees(t *testing.T) {
+	t.Parallel()
+
+	list, ok := decodeFixtureDetails(t, "lcp-breakdown-insight").(*ListDetails)
+	if !ok || len(list.Items) != 2 {
+		t.Fatalf("lcp breakdown = %+v, want a table and a node", list)
+	}
+	if node, ok := list.Items[1].(*NodeValue); !ok || node.Selector != "main > img.hero" {
+		t.Errorf("second item = %+v, want the LCP node", list.Items[1])
+	}
+
+	dependencies, ok := decodeFixtureDetails(t, "network-dependency-tree-insight").(*ListDetails)
+	if !ok || len(dependencies.Items) != 2 {
+		t.Fatalf("dependency tree = %+v", dependencies)
+	}
+	tree, ok := dependencies.Items[0].(*NetworkTreeDetails)
+	if !ok || tree.LongestChain.Duration != 1900 || len(tree.Chains["1000.1"].Children) != 2 {
+		t.Errorf("network tree = %+v", tree)
+	}
+	section, ok := dependencies.Items[1].(*ListSectionDetails)
+	if !ok {
+		t.Fatalf("second item = %T, want list-section", dependencies.Items[1])
+	}
+	if preconnect, ok := section.Value.(*TableDetails); !ok {
+		t.Errorf("section value = %T, want table", section.Value)
+	} else if origin, _ := preconnect.Items[0].Text("origin"); origin != "https://www.googletagmanager.com" {
+		t.Errorf("preconnect origin = %q", origin)
+	}
+
+	chains, ok := decodeFixtureDetails(t, "critical-request-chains").(*CriticalRequestChainDetails)
+	if !ok || chains.LongestChain.Length != 3 {
+		t.Fatalf("critical chains = %+v", chains)
+	}
+	styles := chains.Chains["1000.1"].Children["1000.2"]
+	if styles.Request.URL != "https://example.test/styles.css" || len(styles.Children) != 1 {
+		t.Errorf("styles chain = %+v", styles)
+	}
+
+	treemap, ok := decodeFixtureDetails(t, "script-treemap-data").(*TreemapDataDetails)
+	if !ok || len(treemap.Nodes) != 3 ||
+		treemap.Nodes[0].Children[0].Children[0].DuplicatedNormalizedModuleName != "lodash" {
+		t.Errorf("treemap = %+v", treemap)
+	}
+}
+
+func TestDecodeDetails_ChecklistScreenshotsAndDebugData(t *testing.T) {
+	t.Parallel()
+
+	checklist, ok := decodeFixtureDetails(t, "document-latency-insight").(*ChecklistDetails)
+	if !ok || !checklist.Items["noRedirects"].Value || checklist.Items["usesCompression"].Value {
+		t.Errorf("checklist = %+v", checklist)
+	}
+
+	filmstrip, ok := decodeFixtureDetails(t, "screenshot-thumbnails").(*FilmstripDetails)
+	if !ok || len(filmstrip.Items) != 2 || filmstrip.Items[1].Timing != 3000 ||
+		!strings.HasPrefix(filmstrip.Items[0].Data, "data:image/jpeg;base64,") {
+		t.Errorf("filmstrip = %+v", filmstrip)
+	}
+	if screenshot, ok := decodeFixtureDetails(t, "final-screenshot").(*ScreenshotDetails); !ok ||
+		screenshot.Timing != 3100 {
+		t.Errorf("final screenshot = %+v", screenshot)
+	}
+
+	debug, ok := decodeFixtureDetails(t, "diagnostics").(*DebugDataDetails)
+	if !ok || len(debug.Items) != 1 || debug.Items[0]["numRequests"] != float64(6) || debug.Fields != nil {
+		t.Errorf("diagnostics = %+v", debug)
+	}
+}
+
+func TestDecodeDetails_UnknownAndEmpty(t *testing.T) {
+	t.Parallel()
+
+	raw := json.RawMessage(`{"type":"future-type","value":1}`)
+	details, err := DecodeDetails(raw)
+	if err != nil {
+		t.Fatalf("DecodeDetails: %v", err)
+	}
+	unknown, ok := details.(*UnknownDetails)
+	if !ok || unknown.DetailsType() != "future-type" || string(unknown.Raw) != string(raw) {
+		t.Errorf("unknown = %+v", details)
+	}
+	if details, err := DecodeDetails(json.RawMessage(" null ")); details != nil || err != nil {
+		t.Errorf("null details = %v, %v; want nil", details, err)
+	}
+	if _, err := DecodeDetails(json.RawMessage(`{"type":"table","items":"bad"}`)); err == nil {
+		t.Error("malformed table must fail")
+	}
+
+	list, err := DecodeDetails(json.RawMessage(
+		`{"type":"list","items":[{"type":"table","items":"bad"},{"type":"node","selector":"main > img"}]}`,
+	))
+	if err != nil {
+		t.Fatalf("list with a malformed item: %v", err)
+	}
+	items := list.(*ListDetails).Items
+	if len(items) != 2 || items[0].DetailsType() != DetailsTypeTable {
+		t.Fatalf("list items = %+v, want the malformed table kept", items)
+	}
+	if _, ok := items[0].(*UnknownDetails); !ok {
+		t.Errorf("malformed item = %T, want *UnknownDetails", items[0])
+	}
+	if node, ok := items[1].(*NodeValue); !ok || node.Selector != "main > img" {
+		t.Errorf("node item = %+v, want it decoded despite its malformed sibling", items[1])
+	}
+
+	audit := LighthouseAudit{Details: json.RawMessage(`{"type":"url","value":"https://example.test/"}`)}
+	if value, err := audit.DecodeDetails(); err != nil || value.(*URLValue).Value != "https://example.test/" {
+		t.Errorf("audit details = %+v, %v", value, err)
+	}
+}
@@ -0,0 +1,222 @@
+package pagespeed
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/webvitals"
+)
+
+// discrepancyTolerance is the relative lab-versus-field gap reported as consistent.
+const discrepancyTolerance = 0.2
+
+// Discrepancy directions.
+const (
+	DirectionFieldSlower = "field_slower"
+	DirectionLabSlower   = "lab_slower"
+	DirectionConsistent  = "consistent"
+	DirectionUnavailable = "unavailable"
+)
+
+// Discrepancy compares Lighthouse lab metrics with real-user field p75 values.
+type Discrepancy struct {
+	// FieldSource is page or origin, naming the field data that was compared.
+	FieldSource string `json:"fieldSource"`
+	// OriginFallback reports whether origin data stands in for the page.
+	OriginFallback bool `json:"originFallback"`
+	// Metrics contains one comparison per lab and field metric pair.
+	Metrics []MetricDiscrepancy `json:"metrics"`
+	// Explanations contains run-level reasons why lab and field data differ.
+	Explanations []string `json:"explanations"`
+}
+
+// MetricDiscrepancy compares one lab metric with its field counterpart.
+type MetricDiscrepancy struct {
+	// Field is the field metric name, such as lcp or inp.
+	Field string `json:"field"`
+	// Lab is the lab metric name, such as lcp or tbt.
+	Lab string `json:"lab"`
+	// Proxy reports whether the lab metric only approximates the field metric.
+	Proxy bool `json:"proxy"`
+	// FieldP75 is the real-user 75th-percentile value.
+	FieldP75 *float64 `json:"fieldP75"`
+	// LabValue is the Lighthouse value.
+	LabValue *float64 `json:"labValue"`
+	// Unit identifies the unit of both values.
+	Unit string `json:"unit,omitempty"`
+	// Difference is FieldP75 minus LabValue.
+	Difference *float64 `json:"difference,omitempty"`
+	// Ratio is FieldP75 divided by LabValue.
+	Ratio *float64 `json:"ratio,omitempty"`
+	// Direction is field_slower, lab_slower, consistent, or unavailable.
+	Direction string `json:"direction"`
+	// FieldRating is the rating of the field p75.
+	FieldRating string `json:"fieldRating,omitempty"`
+	// LabRating is the rating of the lab value against the field thresholds;
+	// it is omitted for proxy pairs.
+	LabRating string `json:"labRating,omitempty"`
+	// FieldPoorProportion is the share of real-user experiences rated poor.
+	FieldPoorProportion *float64 `json:"fieldPoorProportion,omitempty"`
+	// Explanation describes the most likely cause of the gap.
+	Explanation string `json:"explanation,omitempty"`
+}
+
+type discrepancyPair struct {
+	field       string
+	lab         string
+	proxy       bool
+	fieldSlower string
+	labSlower   string
+}
+
+var discrepancyPairs = []discrepancyPair{
+	{
+		field:       "lcp",
+		lab:         "lcp",
+		fieldSlower: "Real users load the page on slower devices or networks than the lab profile, or see different LCP elements such as personalized or late-loading content.",
+		labSlower:   "Most real users are faster than the lab profile; repeat visits benefit from HTTP caches, the back/forward cache, and warm connections that a cold lab load lacks.",
+	},
+	{
+		field:       "cls",
+		lab:         "cls",
+		fieldSlower: "Lab CLS covers only the initial load, while field CLS includes shifts after scrolling and interaction, such as lazy-loaded content, ads, and late embeds.",
+		labSlower:   "The lab viewport or cold load triggers shifts that most real visits avoid, for example because fonts and images are cached.",
+	},
+	{
+		field:       "fcp",
+		lab:         "fcp",
+		fieldSlower: "Real users render first content later than the lab profile, usually because of slower devices, networks, or redirects before the page.",
+		labSlower:   "Most real users render first content sooner than the cold, throttled lab load thanks to caching and faster connections.",
+	},
+	{
+		field:       "ttfb",
+		lab:         "serverResponseTime",
+		proxy:       true,
+		fieldSlower: "Field TTFB includes redirects, DNS, connection setup, and service worker startup, while lab server response time measures only the document request; CDN cache misses in some regions also raise field TTFB.",
+		labSlower:   "The lab request missed caches that most real visits hit; field TTFB also benefits from prerendering and the back/forward cache.",
+	},
+	{
+		field:       "inp",
+		lab:         "tbt",
+		proxy:       true,
+		fieldSlower: "TBT measures main-thread blocking during load without interactions, while INP measures real interactions for the whole visit; slow event handlers and rendering after load raise INP without affecting TBT.",
+		labSlower:   "Main-thread work during load is heavy, but real users mostly interact after it finishes; TBT is only a load-time proxy for INP.",
+	},
+}
+
+// AnalyzeDiscrepancy compares lab metrics with page field data, or origin
+// field data when the page has none. It returns nil without both.
+func AnalyzeDiscrepancy(result *AnalysisResult) *Discrepancy {
+	if result == nil || result.LabData == nil || result.FieldData == nil {
+		return nil
+	}
+	experience, source := result.FieldData.Page, "page"
+	if experience == nil {
+		experience, source = result.FieldData.Origin, "origin"
+	}
+	if experience == nil {
+		return nil
+	}
+
+	discrepancy := &Discrepancy{
+		FieldSource:    source,
+		OriginFallback: source == "origin" || experience.OriginFallback,
+		Metrics:        make([]MetricDiscrepancy, 0, len(discrepancyPairs)),
+		Explanations:   []string{},
+	}
+	for _, pair := range discrepancyPairs {
+		entry := MetricDiscrepancy{
+			Field:     pair.field,
+			Lab:       pair.lab,
+			Proxy:     pair.proxy,
+			Direction: DirectionUnavailable,
+		}
+		if metric, ok := experience.Metrics[pair.field]; ok {
+			value := metric.Value
+			entry.FieldP75 = &value
+			entry.Unit = metric.Unit
+			entry.FieldRating = metric.Rating
+			if len(metric.Distributions) > 0 {
+				poor := metric.Distributions[len(metric.Distributions)-1].Proportion
+				entry.FieldPoorProportion = &poor
+			}
+		}
+		if metric, ok := result.LabData.Metrics[pair.lab]; ok && metric.Value != nil {
+			entry.LabValue = metric.Value
+		}
+		compareDiscrepancy(&entry, pair)
+		discrepancy.Metrics = append(discrepancy.Metrics, entry)
+	}
+
+	if discrepancy.OriginFallback {
+		discrepancy.Explanations = append(discrepancy.Explanations,
+			"Field data describes the whole origin rather than this page, so page-specific lab results can legitimately differ.")
+	}
+	if explanation := throttlingExplanation(result.Metadata.ConfigSettings); explanation != "" {
+		discrepancy.Explanations = append(discrepancy.Explanations, explanation)
+	}
+	discrepancy.Explanations = append(discrepancy.Explanations,
+		"Lab data is one synthetic page load; field data is the 75th percentile of 28 days of real visits.")
+	return discrepancy
+}
+
+func compareDiscrepancy(entry *MetricDiscrepancy, pair discrepancyPair) {
+	if entry.FieldP75 == nil || entry.LabValue == nil {
+		return
+	}
+	field, lab := *entry.FieldP75, *entry.LabValue
+	difference := field - lab
+	entry.Difference = &difference
+	if lab != 0 {
+		ratio := field / lab
+		entry.Ratio = &ratio
+	}
+	if !pair.proxy {
+		if threshold, ok := webvitals.ThresholdFor(pair.field); ok {
+			entry.LabRating = threshold.Rate(lab)
+		}
+	}
+
+	scale := math.Max(math.Abs(field), math.Abs(lab))
+	switch {
+	case scale == 0 || math.Abs(difference)/scale <= discrepancyTolerance:
+		entry.Direction = DirectionConsistent
+	case difference > 0:
+		entry.Direction = DirectionFieldSlower
+		entry.Explanation = pair.fieldSlower
+	default:
+		entry.Direction = DirectionLabSlower
+		entry.Explanation = pair.labSlower
+	}
+	if entry.Direction != DirectionConsistent && entry.FieldPoorProportion != nil &&
+		*entry.FieldPoorProportion >= 0.1 {
+		entry.Explanation += fmt.Sprintf(
+			" %.0f%% of real-user experiences are poor, so the field distribution has a long slow tail.",
+			*entry.FieldPoorProportion*100,
+		)
+	}
+}
+
+func throttlingExplanation(settings *ConfigSettings) string {
+	if settings == nil || settings.Throttling == nil {
+		return ""
+	}
+	throttling := settings.Throttling
+	profile := fmt.Sprintf(
+		"%.0f ms RTT, %.0f Kbps throughput, and %gx CPU slowdown",
+		throttling.RTTMs,
+		throttling.ThroughputKbps,
+		throttling.CPUSlowdownMultiplier,
+	)
+	switch settings.ThrottlingMethod {
+	case "simulate":
+		return "Lighthouse simulated a " + settings.FormFactor + " load with " + profile +
+			" from an unthrottled trace; simulation can under- or over-estimate real network conditions."
+	case "devtools":
+		return "Lighthouse applied DevTools throttling for a " + settings.FormFactor + " load with " + profile + "."
+	case "provided":
+		return "Lighthouse ran without throttling, so lab timings reflect the test machine's network and CPU."
+	default:
+		return ""
+	}
+}
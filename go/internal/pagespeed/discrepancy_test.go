@@ -0,0 +1,110 @@
+package pagespeed
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeDiscrepancy_Lighthouse134Fixture(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	discrepancy := result.Discrepancy
+	if discrepancy == nil {
+		t.Fatal("discrepancy must be computed when field and lab data exist")
+	}
+	if discrepancy.FieldSource != "page" || discrepancy.OriginFallback {
+		t.Errorf("source = %q, fallback = %v, want page data", discrepancy.FieldSource, discrepancy.OriginFallback)
+	}
+
+	metrics := make(map[string]MetricDiscrepancy, len(discrepancy.Metrics))
+	for _, metric := range discrepancy.Metrics {
+		metrics[metric.Field] = metric
+	}
+	if lcp := metrics["lcp"]; lcp.Direction != DirectionConsistent || lcp.LabRating != "needs-improvement" {
+		t.Errorf("lcp = %+v, want consistent with a needs-improvement lab rating", lcp)
+	}
+	ttfb := metrics["ttfb"]
+	if ttfb.Lab != "serverResponseTime" || !ttfb.Proxy || ttfb.Direction != DirectionFieldSlower ||
+		ttfb.Difference == nil || *ttfb.Difference != 500 {
+		t.Errorf("ttfb = %+v, want field 500 ms slower than server response time", ttfb)
+	}
+	if ttfb.LabRating != "" {
+		t.Errorf("proxy lab rating = %q, want none", ttfb.LabRating)
+	}
+	if !strings.Contains(ttfb.Explanation, "redirects") ||
+		!strings.Contains(ttfb.Explanation, "10% of real-user experiences are poor") {
+		t.Errorf("ttfb explanation = %q", ttfb.Explanation)
+	}
+	if inp := metrics["inp"]; inp.Lab != "tbt" || inp.Direction != DirectionFieldSlower {
+		t.Errorf("inp = %+v, want field slower than TBT", inp)
+	}
+
+	if len(discrepancy.Explanations) != 2 ||
+		!strings.Contains(discrepancy.Explanations[0], "simulated a mobile load with 150 ms RTT") {
+		t.Errorf("explanations = %v, want throttling profile first", discrepancy.Explanations)
+	}
+	if settings := result.Metadata.ConfigSettings; settings == nil || settings.Throttling == nil ||
+		settings.Throttling.CPUSlowdownMultiplier != 4 {
+		t.Errorf("config settings = %+v, want 4x CPU slowdown", settings)
+	}
+}
+
+func TestAnalyzeDiscrepancy_OriginOnlyFieldData(t *testing.T) {
+	t.Parallel()
+
+	labLCP := 1200.0
+	result := &AnalysisResult{
+		FieldData: &FieldData{Origin: &FieldExperience{
+			Metrics: map[string]FieldMetric{
+				"lcp": {Value: 3800, Unit: "ms", Rating: "needs-improvement"},
+			},
+		}},
+		LabData: &LabData{Metrics: map[string]LabMetric{
+			"lcp": {Value: &labLCP},
+		}},
+	}
+
+	discrepancy := AnalyzeDiscrepancy(result)
+	if discrepancy == nil || discrepancy.FieldSource != "origin" || !discrepancy.OriginFallback {
+		t.Fatalf("discrepancy = %+v, want origin fallback", discrepancy)
+	}
+	lcp := discrepancy.Metrics[0]
+	if lcp.Direction != DirectionFieldSlower || lcp.Ratio == nil || *lcp.Ratio <= 3 {
+		t.Errorf("lcp = %+v, want field more than three times slower", lcp)
+	}
+	if cls := discrepancy.Metrics[1]; cls.Direction != DirectionUnavailable {
+		t.Errorf("cls direction = %q, want unavailable", cls.Direction)
+	}
+	if !strings.Contains(discrepancy.Explanations[0], "whole origin") {
+		t.Errorf("explanations = %v, want origin fallback explanation", discrepancy.Explanations)
+	}
+	if AnalyzeDiscrepancy(&AnalysisResult{LabData: result.LabData}) != nil {
+		t.Error("discrepancy without field data must be nil")
+	}
+}
+
+func TestThrottlingExplanation_DescribesOnlyKnownMethods(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{method: "simulate", want: "simulated"},
+		{method: "devtools", want: "DevTools throttling"},
+		{method: "provided", want: "without throttling"},
+		{method: "", want: ""},
+		{method: "custom", want: ""},
+	}
+	for _, test := range tests {
+		got := throttlingExplanation(&ConfigSettings{
+			FormFactor:       "mobile",
+			ThrottlingMethod: test.method,
+			Throttling:       &Throttling{RTTMs: 150, ThroughputKbps: 1638.4, CPUSlowdownMultiplier: 4},
+		})
+		if test.want == "" && got != "" || !strings.Contains(got, test.want) {
+			t.Errorf("throttlingExplanation(%q) = %q, want %q", test.method, got, test.want)
+		}
+	}
+}
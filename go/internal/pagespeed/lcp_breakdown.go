@@ -0,0 +1,87 @@
+package pagespeed
+
+const lcpBreakdownInsightID = "lcp-breakdown-insight"
+
+// LabLCPBreakdown contains the LCP subparts measured by Lighthouse's
+// lcp-breakdown-insight.
+type LabLCPBreakdown struct {
+	// LCP is the lab LCP in milliseconds when available.
+	LCP *float64 `json:"lcp,omitempty"`
+	// Subparts contains each subpart reported by Lighthouse in loading order.
+	Subparts []LabLCPSubpart `json:"subparts"`
+	// DominantSubpart is the subpart with the longest duration.
+	DominantSubpart string `json:"dominantSubpart,omitempty"`
+}
+
+// LabLCPSubpart contains one Lighthouse LCP subpart duration.
+type LabLCPSubpart struct {
+	// Name is timeToFirstByte, resourceLoadDelay, resourceLoadDuration, or elementRenderDelay.
+	Name string `json:"name"`
+	// Label is the human-readable subpart label.
+	Label string `json:"label,omitempty"`
+	// Duration is the subpart duration in milliseconds.
+	Duration float64 `json:"duration"`
+	// ShareOfLCP is Duration divided by the lab LCP.
+	ShareOfLCP *float64 `json:"shareOfLcp,omitempty"`
+}
+
+// Durations returns the subpart durations keyed by subpart name.
+func (b *LabLCPBreakdown) Durations() map[string]float64 {
+	if b == nil {
+		return nil
+	}
+	durations := make(map[string]float64, len(b.Subparts))
+	for _, subpart := range b.Subparts {
+		durations[subpart.Name] = subpart.Duration
+	}
+	return durations
+}
+
+// parseLCPBreakdown reads the subpart table from lcp-breakdown-insight
+// details. Lighthouse nests the table inside a list next to the LCP element.
+func parseLCPBreakdown(audit *rawAudit, lcp *float64) *LabLCPBreakdown {
+	if audit == nil {
+		return nil
+	}
+	details, err := DecodeDetails(audit.Details)
+	if err != nil || details == nil {
+		return nil
+	}
+
+	breakdown := &LabLCPBreakdown{LCP: lcp, Subparts: []LabLCPSubpart{}}
+	var longest float64
+	var collect func(node AuditDetails)
+	collect = func(node AuditDetails) {
+		switch node := node.(type) {
+		case *ListDetails:
+			for _, item := range node.Items {
+				collect(item)
+			}
+		case *TableDetails:
+			for _, item := range node.Items {
+				name, _ := item.Text("subpart")
+				duration, ok := item.Number("duration")
+				if name == "" || !ok {
+					continue
+				}
+				label, _ := item.Text("label")
+				subpart := LabLCPSubpart{Name: name, Label: label, Duration: duration}
+				if lcp != nil && *lcp > 0 {
+					share := subpart.Duration / *lcp
+					subpart.ShareOfLCP = &share
+				}
+				if breakdown.DominantSubpart == "" || subpart.Duration > longest {
+					longest = subpart.Duration
+					breakdown.DominantSubpart = subpart.Name
+				}
+				breakdown.Subparts = append(breakdown.Subparts, subpart)
+			}
+		}
+	}
+	collect(details)
+
+	if len(breakdown.Subparts) == 0 {
+		return nil
+	}
+	return breakdown
+}
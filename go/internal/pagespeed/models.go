@@ -8,6 +8,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/webvitals"
 )
 
 // AnalysisResult contains one PageSpeed Insights analysis for a URL and strategy.
@@ -18,6 +20,8 @@ type AnalysisResult struct {
 	FieldData *FieldData `json:"fieldData,omitempty"`
 	// LabData contains the synthetic Lighthouse result when it is available.
 	LabData *LabData `json:"labData,omitempty"`
+	// Discrepancy compares lab metrics with field p75 values when both exist.
+	Discrepancy *Discrepancy `json:"labFieldDiscrepancy,omitempty"`
 }
 
 // AnalysisMetadata describes the source and timing of a PageSpeed Insights result.
@@ -44,6 +48,72 @@ type AnalysisMetadata struct {
 	RunWarnings []string `json:"runWarnings"`
 	// RuntimeError identifies a fatal Lighthouse runtime failure.
 	RuntimeError *RuntimeError `json:"runtimeError,omitempty"`
+	// ConfigSettings describes the emulation and throttling used by Lighthouse.
+	ConfigSettings *ConfigSettings `json:"configSettings,omitempty"`
+	// Environment describes the machine and browser that ran Lighthouse.
+	Environment *Environment `json:"environment,omitempty"`
+	// TotalTimeMs is the total Lighthouse run time in milliseconds.
+	TotalTimeMs *float64 `json:"totalTimeMs,omitempty"`
+	// RunQualityWarnings flags conditions that make the lab result noisier.
+	RunQualityWarnings []RunQualityWarning `json:"runQualityWarnings,omitempty"`
+}
+
+// Environment describes the machine and browser that ran Lighthouse.
+type Environment struct {
+	// NetworkUserAgent is the user agent sent with network requests.
+	NetworkUserAgent string `json:"networkUserAgent,omitempty"`
+	// HostUserAgent is the user agent of the Chrome instance running Lighthouse.
+	HostUserAgent string `json:"hostUserAgent,omitempty"`
+	// BenchmarkIndex is the Lighthouse CPU benchmark score of the host.
+	BenchmarkIndex *float64 `json:"benchmarkIndex,omitempty"`
+}
+
+// ConfigSettings contains the Lighthouse settings that shape lab measurements.
+type ConfigSettings struct {
+	// FormFactor is the emulated device class, mobile or desktop.
+	FormFactor string `json:"formFactor,omitempty"`
+	// ThrottlingMethod is simulate, devtools, or provided.
+	ThrottlingMethod string `json:"throttlingMethod,omitempty"`
+	// Throttling contains the network and CPU throttling parameters.
+	Throttling *Throttling `json:"throttling,omitempty"`
+	// ScreenEmulation contains the emulated screen dimensions.
+	ScreenEmulation *ScreenEmulation `json:"screenEmulation,omitempty"`
+	// Locale is the locale Lighthouse used for display strings.
+	Locale string `json:"locale,omitempty"`
+	// OnlyCategories lists the categories Lighthouse ran.
+	OnlyCategories []string `json:"onlyCategories,omitempty"`
+	// Channel identifies the Lighthouse integration, such as lr for PSI.
+	Channel string `json:"channel,omitempty"`
+}
+
+// ScreenEmulation contains Lighthouse screen emulation settings.
+type ScreenEmulation struct {
+	// Mobile reports whether a mobile viewport was emulated.
+	Mobile bool `json:"mobile"`
+	// Width is the viewport width in CSS pixels.
+	Width int `json:"width"`
+	// Height is the viewport height in CSS pixels.
+	Height int `json:"height"`
+	// DeviceScaleFactor is the emulated device pixel ratio.
+	DeviceScaleFactor float64 `json:"deviceScaleFactor"`
+	// Disabled reports whether screen emulation was turned off.
+	Disabled bool `json:"disabled"`
+}
+
+// Throttling contains Lighthouse network and CPU throttling parameters.
+type Throttling struct {
+	// RTTMs is the simulated network round-trip time in milliseconds.
+	RTTMs float64 `json:"rttMs"`
+	// ThroughputKbps is the simulated network throughput.
+	ThroughputKbps float64 `json:"throughputKbps"`
+	// RequestLatencyMs is the applied request latency for devtools throttling.
+	RequestLatencyMs float64 `json:"requestLatencyMs,omitempty"`
+	// DownloadThroughputKbps is the applied download throughput for devtools throttling.
+	DownloadThroughputKbps float64 `json:"downloadThroughputKbps,omitempty"`
+	// UploadThroughputKbps is the applied upload throughput for devtools throttling.
+	UploadThroughputKbps float64 `json:"uploadThroughputKbps,omitempty"`
+	// CPUSlowdownMultiplier is the CPU slowdown factor.
+	CPUSlowdownMultiplier float64 `json:"cpuSlowdownMultiplier"`
 }
 
 // RuntimeError describes a Lighthouse failure that can invalidate the lab result.
@@ -74,6 +144,9 @@ type FieldExperience struct {
 	OriginFallback bool `json:"originFallback"`
 	// Metrics contains available field metrics keyed by stable friendly names.
 	Metrics map[string]FieldMetric `json:"metrics"`
+	// Assessment is the Core Web Vitals verdict computed from the p75 values
+	// with Google's thresholds rather than PSI's OverallRating.
+	Assessment *webvitals.Assessment `json:"coreWebVitalsAssessment,omitempty"`
 }
 
 // FieldMetric contains a p75 real-user metric and its distribution.
@@ -110,6 +183,16 @@ type LabData struct {
 	Metrics map[string]LabMetric `json:"metrics"`
 	// Insights contains actionable Lighthouse 13 insight audits.
 	Insights []LighthouseAudit `json:"insights"`
+	// PrioritizedInsights ranks Insights by estimated performance score gain.
+	PrioritizedInsights []PrioritizedInsight `json:"prioritizedInsights,omitempty"`
+	// LCPBreakdown contains the LCP subparts from lcp-breakdown-insight.
+	LCPBreakdown *LabLCPBreakdown `json:"lcpBreakdown,omitempty"`
+	// LCPElement identifies the Largest Contentful Paint element.
+	LCPElement *LCPElement `json:"lcpElement,omitempty"`
+	// LayoutShiftCulprits contains the shifted elements, highest score first.
+	LayoutShiftCulprits []LayoutShiftCulprit `json:"layoutShiftCulprits,omitempty"`
+	// Screenshots describes the final screenshot and selected filmstrip frames.
+	Screenshots *LabScreenshots `json:"screenshots,omitempty"`
 	// Diagnostics contains failed non-insight audits.
 	Diagnostics []LighthouseAudit `json:"diagnostics"`
 	// UnscoredAudits contains informative audits without a numeric score.
@@ -122,6 +205,12 @@ type LabData struct {
 	ManualAuditIDs []string `json:"manualAuditIds"`
 	// Entities contains Lighthouse first-party and third-party classifications.
 	Entities []Entity `json:"entities"`
+	// StackPacks lists the frameworks and platforms Lighthouse detected.
+	StackPacks []StackPack `json:"stackPacks,omitempty"`
+
+	// passedAudits keeps the passed audits, whose details analyzers such as
+	// ParseThirdPartyReport still read, out of the JSON output.
+	passedAudits []LighthouseAudit
 }
 
 // CategoryResult contains one Lighthouse category result.
@@ -136,6 +225,8 @@ type CategoryResult struct {
 	Score *float64 `json:"score,omitempty"`
 	// ScoreDisplayMode identifies gauge or fractional score rendering.
 	ScoreDisplayMode string `json:"scoreDisplayMode,omitempty"`
+	// Breakdown attributes the score to the category's weighted audits.
+	Breakdown *CategoryBreakdown `json:"breakdown,omitempty"`
 }
 
 // LabMetric contains one synthetic Lighthouse metric result.
@@ -184,6 +275,8 @@ type LighthouseAudit struct {
 	MetricSavings map[string]float64 `json:"metricSavings,omitempty"`
 	// Details preserves the structured Lighthouse audit details object.
 	Details json.RawMessage `json:"details,omitempty"`
+	// StackAdvice contains remediation advice specific to the detected stacks.
+	StackAdvice []StackAdvice `json:"stackAdvice,omitempty"`
 }
 
 // Entity describes a first-party or third-party entity identified by Lighthouse.
@@ -231,17 +324,35 @@ type rawFieldDistribution struct {
 }
 
 type rawLighthouseResult struct {
-	RequestedURL      string                  `json:"requestedUrl"`
-	FinalURL          string                  `json:"finalUrl"`
-	FinalDisplayedURL string                  `json:"finalDisplayedUrl"`
-	MainDocumentURL   string                  `json:"mainDocumentUrl"`
-	LighthouseVersion string                  `json:"lighthouseVersion"`
-	FetchTime         string                  `json:"fetchTime"`
-	RunWarnings       []json.RawMessage       `json:"runWarnings"`
-	RuntimeError      *RuntimeError           `json:"runtimeError"`
-	Categories        map[string]*rawCategory `json:"categories"`
-	Audits            map[string]*rawAudit    `json:"audits"`
-	Entities          []Entity                `json:"entities"`
+	RequestedURL       string                  `json:"requestedUrl"`
+	FinalURL           string                  `json:"finalUrl"`
+	FinalDisplayedURL  string                  `json:"finalDisplayedUrl"`
+	MainDocumentURL    string                  `json:"mainDocumentUrl"`
+	LighthouseVersion  string                  `json:"lighthouseVersion"`
+	FetchTime          string                  `json:"fetchTime"`
+	RunWarnings        []json.RawMessage       `json:"runWarnings"`
+	RuntimeError       *RuntimeError           `json:"runtimeError"`
+	ConfigSettings     *ConfigSettings         `json:"configSettings"`
+	Environment        *Environment            `json:"environment"`
+	Timing             *rawTiming              `json:"timing"`
+	Categories         map[string]*rawCategory `json:"categories"`
+	Audits             map[string]*rawAudit    `json:"audits"`
+	FullPageScreenshot *rawFullPageScreenshot  `json:"fullPageScreenshot"`
+	Entities           []Entity                `json:"entities"`
+	StackPacks         []rawStackPack          `json:"stackPacks"`
+}
+
+type rawTiming struct {
+	Total *float64 `json:"total"`
+}
+
+type rawFullPageScreenshot struct {
+	Screenshot struct {
+		Data   string `json:"data"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	} `json:"screenshot"`
+	Nodes map[string]Rect `json:"nodes"`
 }
 
 type rawCategory struct {
@@ -254,8 +365,9 @@ type rawCategory struct {
 }
 
 type rawAuditRef struct {
-	ID    string `json:"id"`
-	Group string `json:"group"`
+	ID     string  `json:"id"`
+	Weight float64 `json:"weight"`
+	Group  string  `json:"group"`
 }
 
 type rawAudit struct {
@@ -320,7 +432,18 @@ func parseResult(targetURL, strategy string, raw *apiResponse) *AnalysisResult {
 	result.Metadata.MainDocumentURL = lhr.MainDocumentURL
 	result.Metadata.RunWarnings = normalizeJSONMessages(lhr.RunWarnings)
 	result.Metadata.RuntimeError = lhr.RuntimeError
+	result.Metadata.ConfigSettings = lhr.ConfigSettings
+	result.Metadata.Environment = lhr.Environment
+	if lhr.Timing != nil {
+		result.Metadata.TotalTimeMs = lhr.Timing.Total
+	}
+	result.Metadata.RunQualityWarnings = assessRunQuality(result.Metadata)
 	result.LabData = parseLabData(lhr)
+	result.LabData.PrioritizedInsights = prioritizeInsights(
+		result.LabData,
+		performanceCurves(strategy, lhr.Categories["performance"]),
+	)
+	result.Discrepancy = AnalyzeDiscrepancy(result)
 	return result
 }
 
@@ -379,12 +502,20 @@ func parseFieldExperience(raw *rawLoadingExperience) *FieldExperience {
 		}
 	}
 
+	p75s := make(map[string]*float64, len(webvitals.CoreWebVitals))
+	for _, name := range webvitals.CoreWebVitals {
+		if metric, ok := metrics[name]; ok {
+			p75s[name] = &metric.Value
+		}
+	}
+
 	return &FieldExperience{
 		ID:             raw.ID,
 		InitialURL:     raw.InitialURL,
 		OverallRating:  normalizeRating(raw.OverallCategory),
 		OriginFallback: raw.OriginFallback,
 		Metrics:        metrics,
+		Assessment:     webvitals.Assess(p75s),
 	}
 }
 
@@ -440,6 +571,7 @@ func parseLabData(raw *rawLighthouseResult) *LabData {
 			Description:      category.Description,
 			Score:            category.Score,
 			ScoreDisplayMode: normalizeCategoryScoreDisplayMode(category.CategoryScoreDisplayMode),
+			Breakdown:        breakdownCategory(category, raw.Audits),
 		}
 		for _, ref := range category.AuditRefs {
 			if strings.EqualFold(ref.Group, "insights") {
@@ -464,6 +596,14 @@ func parseLabData(raw *rawLighthouseResult) *LabData {
 		}
 	}
 
+	data.LCPBreakdown = parseLCPBreakdown(
+		raw.Audits[lcpBreakdownInsightID],
+		data.Metrics["lcp"].Value,
+	)
+	data.Screenshots = parseScreenshots(raw.Audits, raw.FullPageScreenshot)
+	data.LCPElement = parseLCPElement(raw.Audits, raw.FinalURL)
+	data.LayoutShiftCulprits = parseLayoutShiftCulprits(raw.Audits)
+
 	for id, rawAudit := range raw.Audits {
 		if rawAudit == nil {
 			continue
@@ -482,12 +622,13 @@ func parseLabData(raw *rawLighthouseResult) *LabData {
 			continue
 		}
 
+		audit := normalizeAudit(id, rawAudit)
 		if rawAudit.Score != nil && *rawAudit.Score >= 0.9 {
 			data.PassedAuditIDs = append(data.PassedAuditIDs, id)
+			data.passedAudits = append(data.passedAudits, audit)
 			continue
 		}
 
-		audit := normalizeAudit(id, rawAudit)
 		_, groupedAsInsight := insightIDs[id]
 		if groupedAsInsight || strings.HasSuffix(id, "-insight") {
 			data.Insights = append(data.Insights, audit)
@@ -500,6 +641,9 @@ func parseLabData(raw *rawLighthouseResult) *LabData {
 		data.Diagnostics = append(data.Diagnostics, audit)
 	}
 
+	data.StackPacks = parseStackPacks(raw.StackPacks)
+	applyStackAdvice(data, raw.StackPacks)
+
 	sort.Strings(data.PassedAuditIDs)
 	sort.Strings(data.NotApplicableAuditIDs)
 	sort.Strings(data.ManualAuditIDs)
@@ -509,6 +653,23 @@ func parseLabData(raw *rawLighthouseResult) *LabData {
 	return data
 }
 
+// Audit returns the insight, diagnostic, unscored, or passed audit with the
+// given identifier. Passed audits are found even though the JSON output keeps
+// only their IDs; not applicable and manual audits have no details to return.
+func (d *LabData) Audit(id string) (LighthouseAudit, bool) {
+	if d == nil {
+		return LighthouseAudit{}, false
+	}
+	for _, audits := range [][]LighthouseAudit{d.Insights, d.Diagnostics, d.UnscoredAudits, d.passedAudits} {
+		for _, audit := range audits {
+			if audit.ID == id {
+				return audit, true
+			}
+		}
+	}
+	return LighthouseAudit{}, false
+}
+
 func normalizeCategoryScoreDisplayMode(value string) string {
 	value = strings.TrimPrefix(value, "CATEGORY_SCORE_DISPLAY_MODE_")
 	return strings.ToLower(value)
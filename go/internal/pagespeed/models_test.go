@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -39,6 +40,14 @@ func TestParseResult_Lighthouse134Fixture_SeparatesFieldAndLabData(t *testing.T)
 	if got := result.FieldData.Origin.Metrics["lcp"].Rating; got != "good" {
 		t.Errorf("origin LCP rating = %q, want good", got)
 	}
+	pageAssessment := result.FieldData.Page.Assessment
+	if pageAssessment == nil || pageAssessment.Status != "failed" ||
+		len(pageAssessment.FailingMetrics) != 2 {
+		t.Errorf("page assessment = %+v, want failed on LCP and INP", pageAssessment)
+	}
+	if got := result.FieldData.Origin.Assessment; got == nil || got.Passes == nil || !*got.Passes {
+		t.Errorf("origin assessment = %+v, want passed", got)
+	}
 
 	if result.LabData == nil {
 		t.Fatal("lab data must be present")
@@ -76,6 +85,17 @@ func TestParseResult_Lighthouse134Fixture_PreservesInsightsAndAuditDetails(t *te
 		t.Errorf("insight detail type = %q, want table", details.Type)
 	}
 
+	breakdown := lab.LCPBreakdown
+	if breakdown == nil || len(breakdown.Subparts) != 4 {
+		t.Fatalf("LCP breakdown = %+v, want four lab subparts", breakdown)
+	}
+	if breakdown.DominantSubpart != "resourceLoadDelay" {
+		t.Errorf("lab dominant subpart = %q, want resourceLoadDelay", breakdown.DominantSubpart)
+	}
+	if share := breakdown.Subparts[1].ShareOfLCP; share == nil || *share != 1300.0/3100.0 {
+		t.Errorf("resource load delay share = %v, want 1300/3100", share)
+	}
+
 	findAudit(t, lab.Diagnostics, "uses-text-compression")
 	assertContains(t, lab.PassedAuditIDs, "llms-txt")
 	assertContains(t, lab.ManualAuditIDs, "manual-audit")
@@ -93,6 +113,62 @@ func TestParseResult_Lighthouse134Fixture_PreservesInsightsAndAuditDetails(t *te
 	}
 }
 
+func TestParseResult_Lighthouse134Fixture_ParsesRunEnvironment(t *testing.T) {
+	t.Parallel()
+
+	metadata := parseResult("https://example.test/page", "mobile", loadPSIFixture(t)).Metadata
+	environment := metadata.Environment
+	if environment == nil || environment.BenchmarkIndex == nil || *environment.BenchmarkIndex != 1523.5 ||
+		!strings.Contains(environment.HostUserAgent, "HeadlessChrome") ||
+		!strings.HasSuffix(environment.NetworkUserAgent, "Chrome-Lighthouse") {
+		t.Errorf("environment = %+v", environment)
+	}
+	if metadata.TotalTimeMs == nil || *metadata.TotalTimeMs != 14820.6 {
+		t.Errorf("total time = %v, want 14820.6", metadata.TotalTimeMs)
+	}
+
+	settings := metadata.ConfigSettings
+	if settings == nil || settings.Locale != "en-US" || settings.Channel != "lr" || len(settings.OnlyCategories) != 3 {
+		t.Fatalf("config settings = %+v", settings)
+	}
+	if screen := settings.ScreenEmulation; screen == nil || !screen.Mobile || screen.Width != 412 ||
+		screen.Height != 823 || screen.DeviceScaleFactor != 1.75 || screen.Disabled {
+		t.Errorf("screen emulation = %+v", settings.ScreenEmulation)
+	}
+
+	if len(metadata.RunQualityWarnings) != 1 || metadata.RunQualityWarnings[0].Code != RunQualityLighthouseWarnings {
+		t.Errorf("run quality warnings = %+v, want only lighthouse_warnings", metadata.RunQualityWarnings)
+	}
+}
+
+func TestLabDataAudit_FindsPassedAuditDetails(t *testing.T) {
+	t.Parallel()
+
+	score := 1.0
+	lab := parseResult("https://example.test", "mobile", &apiResponse{
+		LighthouseResult: &rawLighthouseResult{Audits: map[string]*rawAudit{
+			"bootup-time": {
+				ID:      "bootup-time",
+				Score:   &score,
+				Details: json.RawMessage(`{"type":"table","items":[{"url":"https://example.test/app.js","total":40}]}`),
+			},
+		}},
+	}).LabData
+
+	assertContains(t, lab.PassedAuditIDs, "bootup-time")
+	audit, ok := lab.Audit("bootup-time")
+	if !ok || len(audit.Details) == 0 {
+		t.Fatalf("Audit(bootup-time) = %+v, %v, want passed audit with details", audit, ok)
+	}
+	encoded, err := json.Marshal(lab)
+	if err != nil {
+		t.Fatalf("marshal lab data: %v", err)
+	}
+	if strings.Contains(string(encoded), "app.js") {
+		t.Errorf("passed audit details must stay out of JSON: %s", encoded)
+	}
+}
+
 func TestParseResult_WithoutLighthouseOrFieldData_PreservesRequestMetadata(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,295 @@
+package pagespeed
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	networkRequestsAuditID      = "network-requests"
+	networkRTTAuditID           = "network-rtt"
+	networkServerLatencyAuditID = "network-server-latency"
+	// waterfallBarWidth is the number of columns in a rendered waterfall bar.
+	waterfallBarWidth = 40
+)
+
+// NetworkWaterfall contains the requests Lighthouse recorded during load.
+type NetworkWaterfall struct {
+	// Requests contains every request in start order.
+	Requests []NetworkRequest `json:"requests"`
+	// Summary contains totals and breakdowns by resource type and entity.
+	Summary NetworkSummary `json:"summary"`
+	// OriginRTT contains the network round-trip time per origin.
+	OriginRTT []OriginTiming `json:"originRtt,omitempty"`
+	// OriginServerLatency contains the server backend latency per origin.
+	OriginServerLatency []OriginTiming `json:"originServerLatency,omitempty"`
+}
+
+// NetworkRequest is one request from the network-requests audit. Times are in
+// milliseconds after navigation start and sizes are in bytes.
+type NetworkRequest struct {
+	// URL is the request URL.
+	URL string `json:"url"`
+	// ResourceType is the DevTools resource type, such as Script or Image.
+	ResourceType string `json:"resourceType,omitempty"`
+	// MIMEType is the response MIME type.
+	MIMEType string `json:"mimeType,omitempty"`
+	// Protocol is the network protocol, such as h2.
+	Protocol string `json:"protocol,omitempty"`
+	// Priority is the Chrome fetch priority, such as VeryHigh.
+	Priority string `json:"priority,omitempty"`
+	// StatusCode is the HTTP status code.
+	StatusCode int `json:"statusCode,omitempty"`
+	// StartTime is when the network request started.
+	StartTime float64 `json:"startTime"`
+	// EndTime is when the response finished.
+	EndTime float64 `json:"endTime"`
+	// Duration is EndTime minus StartTime.
+	Duration float64 `json:"duration"`
+	// TransferSize is the encoded size transferred over the network.
+	TransferSize float64 `json:"transferSize"`
+	// ResourceSize is the decoded resource size.
+	ResourceSize float64 `json:"resourceSize"`
+	// Entity is the Lighthouse entity that owns the URL.
+	Entity string `json:"entity,omitempty"`
+}
+
+// NetworkSummary contains waterfall totals and breakdowns.
+type NetworkSummary struct {
+	// Requests is the number of requests.
+	Requests int `json:"requests"`
+	// TransferSize is the total transfer size in bytes.
+	TransferSize float64 `json:"transferSize"`
+	// ResourceSize is the total decoded size in bytes.
+	ResourceSize float64 `json:"resourceSize"`
+	// Duration is the end time of the last request in milliseconds.
+	Duration float64 `json:"duration"`
+	// ByResourceType groups requests by resource type, largest transfer first.
+	ByResourceType []NetworkGroup `json:"byResourceType"`
+	// ByEntity groups requests by entity, largest transfer first.
+	ByEntity []NetworkGroup `json:"byEntity"`
+}
+
+// NetworkGroup summarizes the requests sharing a resource type or entity.
+type NetworkGroup struct {
+	// Name is the resource type or entity name.
+	Name string `json:"name"`
+	// IsFirstParty reports whether an entity group is first party.
+	IsFirstParty *bool `json:"isFirstParty,omitempty"`
+	// Requests is the number of requests.
+	Requests int `json:"requests"`
+	// TransferSize is the total transfer size in bytes.
+	TransferSize float64 `json:"transferSize"`
+	// ResourceSize is the total decoded size in bytes.
+	ResourceSize float64 `json:"resourceSize"`
+	// TransferShare is TransferSize divided by the waterfall total.
+	TransferShare float64 `json:"transferShare"`
+}
+
+// OriginTiming is a per-origin network timing in milliseconds.
+type OriginTiming struct {
+	// Origin is the request origin.
+	Origin string `json:"origin"`
+	// Time is the timing in milliseconds.
+	Time float64 `json:"time"`
+}
+
+// ParseNetworkWaterfall builds the waterfall from the network-requests,
+// network-rtt, and network-server-latency audits. It fails when the
+// network-requests table is missing.
+func ParseNetworkWaterfall(lab *LabData) (*NetworkWaterfall, error) {
+	audit, ok := lab.Audit(networkRequestsAuditID)
+	if !ok {
+		return nil, fmt.Errorf("lighthouse result has no %s audit", networkRequestsAuditID)
+	}
+	details, err := audit.DecodeDetails()
+	if err != nil {
+		return nil, err
+	}
+	table, ok := details.(*TableDetails)
+	if !ok {
+		return nil, fmt.Errorf("%s details are not a table", networkRequestsAuditID)
+	}
+
+	waterfall := &NetworkWaterfall{Requests: make([]NetworkRequest, 0, len(table.Items))}
+	for _, item := range table.Items {
+		request := NetworkRequest{}
+		request.URL, _ = item.Text("url")
+		if request.URL == "" {
+			continue
+		}
+		request.ResourceType, _ = item.Text("resourceType")
+		request.MIMEType, _ = item.Text("mimeType")
+		request.Protocol, _ = item.Text("protocol")
+		request.Priority, _ = item.Text("priority")
+		request.Entity, _ = item.Text("entity")
+		if status, ok := item.Number("statusCode"); ok {
+			request.StatusCode = int(status)
+		}
+		request.StartTime, _ = item.Number("networkRequestTime")
+		request.EndTime, _ = item.Number("networkEndTime")
+		request.Duration = max(0, request.EndTime-request.StartTime)
+		request.TransferSize, _ = item.Number("transferSize")
+		request.ResourceSize, _ = item.Number("resourceSize")
+		waterfall.Requests = append(waterfall.Requests, request)
+	}
+	sort.SliceStable(waterfall.Requests, func(i, j int) bool {
+		return waterfall.Requests[i].StartTime < waterfall.Requests[j].StartTime
+	})
+
+	waterfall.Summary = summarizeNetwork(waterfall.Requests, lab.Entities)
+	waterfall.OriginRTT = parseOriginTimings(lab, networkRTTAuditID, "rtt")
+	waterfall.OriginServerLatency = parseOriginTimings(lab, networkServerLatencyAuditID, "serverResponseTime")
+	return waterfall, nil
+}
+
+func summarizeNetwork(requests []NetworkRequest, entities []Entity) NetworkSummary {
+	summary := NetworkSummary{Requests: len(requests)}
+	byType := make(map[string]*NetworkGroup)
+	byEntity := make(map[string]*NetworkGroup)
+	add := func(groups map[string]*NetworkGroup, name string, request NetworkRequest) *NetworkGroup {
+		group, ok := groups[name]
+		if !ok {
+			group = &NetworkGroup{Name: name}
+			groups[name] = group
+		}
+		group.Requests++
+		group.TransferSize += request.TransferSize
+		group.ResourceSize += request.ResourceSize
+		return group
+	}
+
+	firstParty := make(map[string]bool, len(entities))
+	for _, entity := range entities {
+		firstParty[entity.Name] = entity.IsFirstParty
+	}
+	for _, request := range requests {
+		summary.TransferSize += request.TransferSize
+		summary.ResourceSize += request.ResourceSize
+		summary.Duration = max(summary.Duration, request.EndTime)
+		add(byType, valueOr(request.ResourceType, "Other"), request)
+
+		entity := request.Entity
+		if entity == "" {
+			entity = requestOrigin(request.URL)
+		}
+		group := add(byEntity, entity, request)
+		if isFirstParty, ok := firstParty[request.Entity]; ok {
+			group.IsFirstParty = &isFirstParty
+		}
+	}
+	summary.ByResourceType = sortNetworkGroups(byType, summary.TransferSize)
+	summary.ByEntity = sortNetworkGroups(byEntity, summary.TransferSize)
+	return summary
+}
+
+func sortNetworkGroups(groups map[string]*NetworkGroup, total float64) []NetworkGroup {
+	sorted := make([]NetworkGroup, 0, len(groups))
+	for _, group := range groups {
+		if total > 0 {
+			group.TransferShare = group.TransferSize / total
+		}
+		sorted = append(sorted, *group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].TransferSize != sorted[j].TransferSize {
+			return sorted[i].TransferSize > sorted[j].TransferSize
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func parseOriginTimings(lab *LabData, auditID, key string) []OriginTiming {
+	audit, ok := lab.Audit(auditID)
+	if !ok {
+		return nil
+	}
+	details, err := audit.DecodeDetails()
+	if err != nil {
+		return nil
+	}
+	table, ok := details.(*TableDetails)
+	if !ok {
+		return nil
+	}
+	timings := make([]OriginTiming, 0, len(table.Items))
+	for _, item := range table.Items {
+		origin, _ := item.Text("origin")
+		time, ok := item.Number(key)
+		if origin == "" || !ok {
+			continue
+		}
+		timings = append(timings, OriginTiming{Origin: origin, Time: time})
+	}
+	sort.SliceStable(timings, func(i, j int) bool { return timings[i].Time > timings[j].Time })
+	return timings
+}
+
+func requestOrigin(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return "Other"
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// Text renders the waterfall as a fixed-width chart with one request per
+// line, scaled to the last request end time.
+func (w *NetworkWaterfall) Text() string {
+	var builder strings.Builder
+	scale := w.Summary.Duration
+	fmt.Fprintf(&builder, "%d requests, %s transferred, %.0f ms\n",
+		w.Summary.Requests, formatBytes(w.Summary.TransferSize), scale)
+	fmt.Fprintf(&builder, "%7s %7s %9s  %-10s %-*s  %s\n",
+		"start", "ms", "size", "type", waterfallBarWidth+2, "timeline", "url")
+	for _, request := range w.Requests {
+		fmt.Fprintf(&builder, "%7.0f %7.0f %9s  %-10s |%s|  %s\n",
+			request.StartTime,
+			request.Duration,
+			formatBytes(request.TransferSize),
+			truncate(valueOr(request.ResourceType, "Other"), 10),
+			waterfallBar(request, scale),
+			request.URL,
+		)
+	}
+	return builder.String()
+}
+
+func waterfallBar(request NetworkRequest, scale float64) string {
+	if scale <= 0 {
+		return strings.Repeat(" ", waterfallBarWidth)
+	}
+	start := min(waterfallBarWidth-1, int(request.StartTime/scale*waterfallBarWidth))
+	length := max(1, int(request.Duration/scale*waterfallBarWidth+0.5))
+	length = min(length, waterfallBarWidth-start)
+	return strings.Repeat(" ", start) + strings.Repeat("=", length) +
+		strings.Repeat(" ", waterfallBarWidth-start-length)
+}
+
+func formatBytes(bytes float64) string {
+	switch {
+	case bytes >= 1024*1024:
+		return fmt.Sprintf("%.1f MiB", bytes/(1024*1024))
+	case bytes >= 1024:
+		return fmt.Sprintf("%.1f KiB", bytes/1024)
+	default:
+		return fmt.Sprintf("%.0f B", bytes)
+	}
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return value[:length]
+}
@@ -0,0 +1,74 @@
+package pagespeed
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseNetworkWaterfall_SummarizesFixture(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	waterfall, err := ParseNetworkWaterfall(result.LabData)
+	if err != nil {
+		t.Fatalf("ParseNetworkWaterfall: %v", err)
+	}
+	if len(waterfall.Requests) != 6 {
+		t.Fatalf("requests = %d, want 6", len(waterfall.Requests))
+	}
+	document := waterfall.Requests[0]
+	if document.ResourceType != "Document" || document.StartTime != 2 || document.Duration != 450 ||
+		document.StatusCode != 200 || document.Priority != "VeryHigh" || document.Entity != "Example" {
+		t.Errorf("document = %+v", document)
+	}
+	for index := 1; index < len(waterfall.Requests); index++ {
+		if waterfall.Requests[index].StartTime < waterfall.Requests[index-1].StartTime {
+			t.Errorf("request %d starts before request %d", index, index-1)
+		}
+	}
+
+	summary := waterfall.Summary
+	if summary.Requests != 6 || summary.TransferSize != 378000 || summary.Duration != 2650 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if image := summary.ByResourceType[0]; image.Name != "Image" || image.Requests != 1 || image.TransferSize != 210000 {
+		t.Errorf("largest resource type = %+v, want Image", image)
+	}
+	if len(summary.ByEntity) != 2 {
+		t.Fatalf("entities = %+v, want Example and Google Tag Manager", summary.ByEntity)
+	}
+	gtm := summary.ByEntity[1]
+	if gtm.Name != "Google Tag Manager" || gtm.Requests != 1 || gtm.IsFirstParty == nil || *gtm.IsFirstParty {
+		t.Errorf("third-party entity = %+v", gtm)
+	}
+	if len(waterfall.OriginRTT) != 2 || waterfall.OriginRTT[0].Time != 150 ||
+		waterfall.OriginServerLatency[0].Origin != "https://example.test" {
+		t.Errorf("origin timings = %+v / %+v", waterfall.OriginRTT, waterfall.OriginServerLatency)
+	}
+}
+
+func TestNetworkWaterfall_TextRendersScaledBars(t *testing.T) {
+	t.Parallel()
+
+	waterfall := &NetworkWaterfall{
+		Requests: []NetworkRequest{
+			{URL: "https://example.test/", ResourceType: "Document", StartTime: 0, EndTime: 500, Duration: 500, TransferSize: 2048},
+			{URL: "https://example.test/app.js", ResourceType: "Script", StartTime: 500, EndTime: 1000, Duration: 500},
+		},
+		Summary: NetworkSummary{Requests: 2, TransferSize: 2048, Duration: 1000},
+	}
+	lines := strings.Split(strings.TrimSpace(waterfall.Text()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "2 requests, 2.0 KiB transferred, 1000 ms") {
+		t.Fatalf("waterfall = %q", lines)
+	}
+	first := lines[2][strings.Index(lines[2], "|"):]
+	second := lines[3][strings.Index(lines[3], "|"):]
+	if !strings.HasPrefix(first, "|"+strings.Repeat("=", 20)+strings.Repeat(" ", 20)+"|") ||
+		!strings.HasPrefix(second, "|"+strings.Repeat(" ", 20)+strings.Repeat("=", 20)+"|") {
+		t.Errorf("bars = %q / %q", first, second)
+	}
+
+	if _, err := ParseNetworkWaterfall(&LabData{}); err == nil {
+		t.Error("a result without network-requests must fail")
+	}
+}
@@ -0,0 +1,163 @@
+package pagespeed
+
+import (
+	"sort"
+	"strings"
+)
+
+// Insight prioritization confidence levels.
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// largeSavingsShare is the share of a metric value above which Lighthouse's
+// savings estimate is treated as less reliable.
+const largeSavingsShare = 0.5
+
+// savingsMetrics maps Lighthouse metricSavings keys to lab metric names.
+var savingsMetrics = map[string]string{
+	"FCP": "fcp",
+	"LCP": "lcp",
+	"TBT": "tbt",
+	"CLS": "cls",
+}
+
+// PrioritizedInsight ranks one Lighthouse insight by its estimated
+// performance score gain.
+type PrioritizedInsight struct {
+	// Rank is the one-based position, highest estimated gain first.
+	Rank int `json:"rank"`
+	// ID is the Lighthouse audit identifier.
+	ID string `json:"id"`
+	// Title is the human-readable audit title.
+	Title string `json:"title"`
+	// EstimatedScoreGain is the projected performance score gain in points
+	// out of 100 if the insight's metric savings were fully realized.
+	EstimatedScoreGain float64 `json:"estimatedScoreGain"`
+	// MetricGains contains the gain in points contributed by each metric.
+	MetricGains map[string]float64 `json:"metricGains,omitempty"`
+	// Confidence is high, medium, or low.
+	Confidence string `json:"confidence"`
+	// ConfidenceReason explains the confidence level.
+	ConfidenceReason string `json:"confidenceReason"`
+}
+
+// prioritizeInsights estimates the score gain of each insight by applying its
+// metric savings to the lab metrics on the scoring curves. Gains are computed
+// per insight; savings from different insights overlap and are not additive.
+func prioritizeInsights(lab *LabData, curves map[string]ScoringCurve) []PrioritizedInsight {
+	if lab == nil || curves == nil {
+		return nil
+	}
+	values := make(map[string]float64, len(ScoredMetrics))
+	for _, name := range ScoredMetrics {
+		if metric, ok := lab.Metrics[name]; ok && metric.Value != nil {
+			values[name] = *metric.Value
+		}
+	}
+
+	prioritized := make([]PrioritizedInsight, 0, len(lab.Insights))
+	for _, insight := range lab.Insights {
+		entry := PrioritizedInsight{
+			ID:          insight.ID,
+			Title:       insight.Title,
+			MetricGains: make(map[string]float64),
+		}
+		largeSavings, scoredSavings := false, false
+		for key, savings := range insight.MetricSavings {
+			name, ok := savingsMetrics[strings.ToUpper(key)]
+			value, measured := values[name]
+			if !ok || !measured || savings <= 0 {
+				continue
+			}
+			scoredSavings = true
+			if value > 0 && savings/value >= largeSavingsShare {
+				largeSavings = true
+			}
+			curve := curves[name]
+			gain := (curve.Score(max(0, value-savings)) - curve.Score(value)) * curve.Weight
+			if gain > 0 {
+				entry.MetricGains[name] = roundScore(gain)
+				entry.EstimatedScoreGain += gain
+			}
+		}
+		entry.EstimatedScoreGain = roundScore(entry.EstimatedScoreGain)
+		if len(entry.MetricGains) == 0 {
+			entry.MetricGains = nil
+		}
+		entry.Confidence, entry.ConfidenceReason = insightConfidence(entry, scoredSavings, largeSavings)
+		prioritized = append(prioritized, entry)
+	}
+
+	sort.SliceStable(prioritized, func(i, j int) bool {
+		if prioritized[i].EstimatedScoreGain != prioritized[j].EstimatedScoreGain {
+			return prioritized[i].EstimatedScoreGain > prioritized[j].EstimatedScoreGain
+		}
+		return confidenceRank(prioritized[i].Confidence) < confidenceRank(prioritized[j].Confidence)
+	})
+	for index := range prioritized {
+		prioritized[index].Rank = index + 1
+	}
+	return prioritized
+}
+
+func insightConfidence(entry PrioritizedInsight, scoredSavings, largeSavings bool) (string, string) {
+	switch {
+	case !scoredSavings:
+		return ConfidenceLow, "Lighthouse reports no savings for scored lab metrics."
+	case entry.EstimatedScoreGain == 0:
+		return ConfidenceLow, "The savings do not move any metric to a higher score."
+	case largeSavings:
+		return ConfidenceMedium, "The savings are at least half of a metric's value, which Lighthouse estimates less reliably."
+	case entry.MetricGains["tbt"] > 0 || entry.MetricGains["cls"] > 0:
+		return ConfidenceMedium, "TBT and CLS savings depend on main-thread and layout behavior that varies between runs."
+	default:
+		return ConfidenceHigh, "The gain comes from simulated FCP or LCP savings that are small relative to the metric."
+	}
+}
+
+func confidenceRank(confidence string) int {
+	switch confidence {
+	case ConfidenceHigh:
+		return 0
+	case ConfidenceMedium:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// performanceCurves returns the scoring curves for a strategy, using the
+// performance category's audit weights when it weights every scored metric.
+func performanceCurves(strategy string, category *rawCategory) map[string]ScoringCurve {
+	defaults, err := ScoringCurves(strategy)
+	if err != nil {
+		return nil
+	}
+	if category == nil {
+		return defaults
+	}
+
+	weights := make(map[string]float64, len(ScoredMetrics))
+	var total float64
+	for _, ref := range category.AuditRefs {
+		if name, ok := labMetricNames[ref.ID]; ok && ref.Weight > 0 {
+			weights[name] = ref.Weight
+			total += ref.Weight
+		}
+	}
+	for _, name := range ScoredMetrics {
+		if _, ok := weights[name]; !ok {
+			return defaults
+		}
+	}
+
+	curves := make(map[string]ScoringCurve, len(defaults))
+	for name, curve := range defaults {
+		curve.Weight = weights[name] / total * 100
+		curves[name] = curve
+	}
+	return curves
+}
@@ -0,0 +1,52 @@
+package pagespeed
+
+import "testing"
+
+func TestPrioritizeInsights_RanksFixtureByScoreGain(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	prioritized := result.LabData.PrioritizedInsights
+	if len(prioritized) != len(result.LabData.Insights) {
+		t.Fatalf("prioritized = %d, want %d", len(prioritized), len(result.LabData.Insights))
+	}
+	first := prioritized[0]
+	if first.Rank != 1 || first.ID != "render-blocking-insight" || first.EstimatedScoreGain <= 0 {
+		t.Fatalf("first = %+v, want render-blocking-insight with a positive gain", first)
+	}
+	if first.MetricGains["lcp"] <= 0 || first.Confidence == ConfidenceLow {
+		t.Errorf("first = %+v, want an LCP gain with useful confidence", first)
+	}
+	last := prioritized[len(prioritized)-1]
+	if last.EstimatedScoreGain != 0 || last.Confidence != ConfidenceLow {
+		t.Errorf("last = %+v, want no gain and low confidence", last)
+	}
+	for index := 1; index < len(prioritized); index++ {
+		if prioritized[index].EstimatedScoreGain > prioritized[index-1].EstimatedScoreGain {
+			t.Errorf("rank %d gain %v exceeds rank %d gain %v", index+1,
+				prioritized[index].EstimatedScoreGain, index, prioritized[index-1].EstimatedScoreGain)
+		}
+	}
+}
+
+func TestPerformanceCurves_NormalizesCategoryWeights(t *testing.T) {
+	t.Parallel()
+
+	category := &rawCategory{AuditRefs: []rawAuditRef{
+		{ID: "first-contentful-paint", Weight: 1},
+		{ID: "speed-index", Weight: 1},
+		{ID: "largest-contentful-paint", Weight: 3},
+		{ID: "total-blocking-time", Weight: 3},
+		{ID: "cumulative-layout-shift", Weight: 2},
+		{ID: "unused-javascript", Weight: 0},
+	}}
+	curves := performanceCurves("desktop", category)
+	if curves["lcp"].Weight != 30 || curves["cls"].Weight != 20 || curves["lcp"].P10 != 1200 {
+		t.Errorf("curves = %+v, want normalized desktop weights", curves)
+	}
+
+	category.AuditRefs = category.AuditRefs[:2]
+	if curves := performanceCurves("mobile", category); curves["tbt"].Weight != 30 {
+		t.Errorf("partial weights = %+v, want Lighthouse defaults", curves)
+	}
+}
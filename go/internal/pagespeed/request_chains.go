@@ -0,0 +1,278 @@
+package pagespeed
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	networkDependencyTreeAuditID = "network-dependency-tree-insight"
+	criticalRequestChainsAuditID = "critical-request-chains"
+)
+
+// RequestChains is the tree of render-critical requests and the requests they
+// initiated. Times are in milliseconds and sizes are in bytes.
+type RequestChains struct {
+	// Source is the audit the tree was read from.
+	Source string `json:"source"`
+	// Roots contains the chain roots, usually the main document.
+	Roots []RequestChainNode `json:"roots"`
+	// Requests is the number of requests in the tree.
+	Requests int `json:"requests"`
+	// MaxDepth is the number of requests in the deepest chain.
+	MaxDepth int `json:"maxDepth"`
+	// LongestChain is the chain that finishes last.
+	LongestChain RequestChainSummary `json:"longestChain"`
+}
+
+// RequestChainNode is one request in the chain tree.
+type RequestChainNode struct {
+	// URL is the request URL.
+	URL string `json:"url"`
+	// StartTime is when the request started. The Lighthouse 13 network
+	// dependency tree reports only end times.
+	StartTime *float64 `json:"startTime,omitempty"`
+	// EndTime is when the response finished.
+	EndTime float64 `json:"endTime"`
+	// TransferSize is the transfer size.
+	TransferSize float64 `json:"transferSize"`
+	// Depth is the 1-based position of the request in its chain.
+	Depth int `json:"depth"`
+	// IsLongest reports whether the request is on the longest chain.
+	IsLongest bool `json:"isLongest,omitempty"`
+	// Children contains the requests initiated by this one, earliest end first.
+	Children []RequestChainNode `json:"children,omitempty"`
+}
+
+// RequestChainSummary describes the longest request chain.
+type RequestChainSummary struct {
+	// Duration is the end time of the chain's last request.
+	Duration float64 `json:"duration"`
+	// Depth is the number of requests in the chain.
+	Depth int `json:"depth"`
+	// TransferSize is the total transfer size of the chain.
+	TransferSize float64 `json:"transferSize"`
+	// URLs lists the chain's requests from root to leaf.
+	URLs []string `json:"urls"`
+}
+
+// ParseRequestChains decodes the Lighthouse 13 network dependency tree, or
+// the earlier critical-request-chains audit, into a typed tree. Legacy times
+// are converted from seconds on the trace clock to milliseconds after the
+// first request started. It fails when neither audit has a tree.
+func ParseRequestChains(lab *LabData) (*RequestChains, error) {
+	var chains *RequestChains
+	if audit, ok := lab.Audit(networkDependencyTreeAuditID); ok {
+		if details, err := audit.DecodeDetails(); err == nil {
+			if tree := findNetworkTree(details); tree != nil {
+				chains = &RequestChains{
+					Source: networkDependencyTreeAuditID,
+					Roots:  networkTreeNodes(tree.Chains),
+				}
+			}
+		}
+	}
+	if chains == nil {
+		if audit, ok := lab.Audit(criticalRequestChainsAuditID); ok {
+			if details, err := audit.DecodeDetails(); err == nil {
+				if tree, ok := details.(*CriticalRequestChainDetails); ok {
+					origin := math.Inf(1)
+					for _, root := range tree.Chains {
+						origin = min(origin, root.Request.StartTime)
+					}
+					chains = &RequestChains{
+						Source: criticalRequestChainsAuditID,
+						Roots:  criticalRequestNodes(tree.Chains, origin),
+					}
+				}
+			}
+		}
+	}
+	if chains == nil {
+		return nil, fmt.Errorf(
+			"lighthouse result has no %s or %s tree",
+			networkDependencyTreeAuditID,
+			criticalRequestChainsAuditID,
+		)
+	}
+
+	sortChainNodes(chains.Roots)
+	var longest []*RequestChainNode
+	walkChains(chains.Roots, 1, nil, func(node *RequestChainNode, path []*RequestChainNode) {
+		chains.Requests++
+		chains.MaxDepth = max(chains.MaxDepth, node.Depth)
+		if len(node.Children) == 0 && (longest == nil || node.EndTime > longest[len(longest)-1].EndTime) {
+			longest = append([]*RequestChainNode(nil), path...)
+		}
+	})
+	for _, node := range longest {
+		node.IsLongest = true
+		chains.LongestChain.TransferSize += node.TransferSize
+		chains.LongestChain.URLs = append(chains.LongestChain.URLs, node.URL)
+	}
+	if len(longest) > 0 {
+		chains.LongestChain.Duration = longest[len(longest)-1].EndTime
+		chains.LongestChain.Depth = len(longest)
+	}
+	return chains, nil
+}
+
+func findNetworkTree(details AuditDetails) *NetworkTreeDetails {
+	switch details := details.(type) {
+	case *NetworkTreeDetails:
+		return details
+	case *ListDetails:
+		for _, item := range details.Items {
+			if tree := findNetworkTree(item); tree != nil {
+				return tree
+			}
+		}
+	case *ListSectionDetails:
+		return findNetworkTree(details.Value)
+	}
+	return nil
+}
+
+func networkTreeNodes(chains map[string]NetworkTreeNode) []RequestChainNode {
+	nodes := make([]RequestChainNode, 0, len(chains))
+	for _, chain := range chains {
+		nodes = append(nodes, RequestChainNode{
+			URL:          chain.URL,
+			EndTime:      chain.NavStartToEndTime,
+			TransferSize: chain.TransferSize,
+			Children:     networkTreeNodes(chain.Children),
+		})
+	}
+	return nodes
+}
+
+func criticalRequestNodes(chains map[string]CriticalRequestNode, origin float64) []RequestChainNode {
+	nodes := make([]RequestChainNode, 0, len(chains))
+	for _, chain := range chains {
+		startTime := roundMilliseconds(chain.Request.StartTime - origin)
+		nodes = append(nodes, RequestChainNode{
+			URL:          chain.Request.URL,
+			StartTime:    &startTime,
+			EndTime:      roundMilliseconds(chain.Request.EndTime - origin),
+			TransferSize: chain.Request.TransferSize,
+			Children:     criticalRequestNodes(chain.Children, origin),
+		})
+	}
+	return nodes
+}
+
+func roundMilliseconds(seconds float64) float64 {
+	return math.Round(seconds * 1000)
+}
+
+func sortChainNodes(nodes []RequestChainNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].EndTime != nodes[j].EndTime {
+			return nodes[i].EndTime < nodes[j].EndTime
+		}
+		return nodes[i].URL < nodes[j].URL
+	})
+	for index := range nodes {
+		if len(nodes[index].Children) == 0 {
+			nodes[index].Children = nil
+			continue
+		}
+		sortChainNodes(nodes[index].Children)
+	}
+}
+
+// walkChains visits every node depth first, setting its depth and passing
+// the path from its root.
+func walkChains(
+	nodes []RequestChainNode,
+	depth int,
+	path []*RequestChainNode,
+	visit func(node *RequestChainNode, path []*RequestChainNode),
+) {
+	for index := range nodes {
+		node := &nodes[index]
+		node.Depth = depth
+		nodePath := append(path[:len(path):len(path)], node)
+		visit(node, nodePath)
+		walkChains(node.Children, depth+1, nodePath, visit)
+	}
+}
+
+// Text renders the tree with box-drawing indentation. Requests on the
+// longest chain are marked with an asterisk.
+func (c *RequestChains) Text() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Longest chain: %.0f ms, %d requests, %s (* marks the longest chain)\n",
+		c.LongestChain.Duration, c.LongestChain.Depth, formatBytes(c.LongestChain.TransferSize))
+	for _, root := range c.Roots {
+		writeChainNode(&builder, root, "", "")
+	}
+	return builder.String()
+}
+
+func writeChainNode(builder *strings.Builder, node RequestChainNode, linePrefix, childPrefix string) {
+	marker := ""
+	if node.IsLongest {
+		marker = " *"
+	}
+	fmt.Fprintf(builder, "%s%s (%s, %s)%s\n", linePrefix, node.URL, chainTiming(node),
+		formatBytes(node.TransferSize), marker)
+	for index, child := range node.Children {
+		if index == len(node.Children)-1 {
+			writeChainNode(builder, child, childPrefix+"└─ ", childPrefix+"   ")
+		} else {
+			writeChainNode(builder, child, childPrefix+"├─ ", childPrefix+"│  ")
+		}
+	}
+}
+
+func chainTiming(node RequestChainNode) string {
+	if node.StartTime == nil {
+		return fmt.Sprintf("ends %.0f ms", node.EndTime)
+	}
+	return fmt.Sprintf("%.0f-%.0f ms", *node.StartTime, node.EndTime)
+}
+
+// Mermaid renders the tree as a left-to-right Mermaid flowchart with the
+// longest chain highlighted.
+func (c *RequestChains) Mermaid() string {
+	var builder strings.Builder
+	builder.WriteString("flowchart LR\n")
+	var longest []string
+	next := 0
+	var write func(nodes []RequestChainNode, parent string)
+	write = func(nodes []RequestChainNode, parent string) {
+		for _, node := range nodes {
+			id := fmt.Sprintf("n%d", next)
+			next++
+			fmt.Fprintf(&builder, "  %s[\"%s<br/>%s, %s\"]\n", id, mermaidLabel(node.URL), chainTiming(node),
+				formatBytes(node.TransferSize))
+			if parent != "" {
+				fmt.Fprintf(&builder, "  %s --> %s\n", parent, id)
+			}
+			if node.IsLongest {
+				longest = append(longest, id)
+			}
+			write(node.Children, id)
+		}
+	}
+	write(c.Roots, "")
+	if len(longest) > 0 {
+		builder.WriteString("  classDef longest stroke:#d33,stroke-width:3px\n")
+		fmt.Fprintf(&builder, "  class %s longest\n", strings.Join(longest, ","))
+	}
+	return builder.String()
+}
+
+// mermaidLabel strips the scheme from a URL and escapes characters that end
+// a Mermaid label.
+func mermaidLabel(rawURL string) string {
+	label := rawURL
+	if _, rest, ok := strings.Cut(label, "://"); ok {
+		label = rest
+	}
+	label = truncate(label, 60)
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(label)
+}
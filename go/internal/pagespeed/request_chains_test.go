@@ -0,0 +1,100 @@
+package pagespeed
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRequestChains_NetworkDependencyTree(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	chains, err := ParseRequestChains(result.LabData)
+	if err != nil {
+		t.Fatalf("ParseRequestChains: %v", err)
+	}
+	if chains.Source != networkDependencyTreeAuditID || chains.Requests != 4 || chains.MaxDepth != 3 {
+		t.Errorf("chains = %+v", chains)
+	}
+	wantURLs := []string{
+		"https://example.test/final",
+		"https://example.test/styles.css",
+		"https://example.test/fonts/inter.woff2",
+	}
+	longest := chains.LongestChain
+	if longest.Duration != 1900 || longest.Depth != 3 || longest.TransferSize != 82000 ||
+		!reflect.DeepEqual(longest.URLs, wantURLs) {
+		t.Errorf("longest chain = %+v", longest)
+	}
+
+	document := chains.Roots[0]
+	if len(document.Children) != 2 || document.Children[0].URL != "https://example.test/styles.css" ||
+		document.Children[1].IsLongest || document.Children[1].Depth != 2 || document.StartTime != nil {
+		t.Errorf("document = %+v", document)
+	}
+
+	text := chains.Text()
+	for _, want := range []string{
+		"Longest chain: 1900 ms, 3 requests, 80.1 KiB",
+		"├─ https://example.test/styles.css (ends 1180 ms, 23.4 KiB) *",
+		"│  └─ https://example.test/fonts/inter.woff2 (ends 1900 ms, 39.1 KiB) *",
+		"└─ https://example.test/app.js (ends 1420 ms, 50.8 KiB)\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text tree missing %q:\n%s", want, text)
+		}
+	}
+
+	mermaid := chains.Mermaid()
+	for _, want := range []string{
+		"flowchart LR\n",
+		`n0["example.test/final<br/>ends 452 ms, 17.6 KiB"]`,
+		"n0 --> n1",
+		"class n0,n1,n2 longest",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("mermaid missing %q:\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestParseRequestChains_LegacyCriticalRequestChains(t *testing.T) {
+	t.Parallel()
+
+	lab := &LabData{UnscoredAudits: []LighthouseAudit{{
+		ID: criticalRequestChainsAuditID,
+		Details: []byte(`{"type":"criticalrequestchain","chains":{
+			"1":{"request":{"url":"https://example.test/","startTime":10,"endTime":10.5,"transferSize":100},
+				"children":{"2":{"request":{"url":"https://example.test/a.css","startTime":10.6,"endTime":11.25,"transferSize":50}}}}
+		},"longestChain":{"duration":1250,"length":2,"transferSize":150}}`),
+	}}}
+	chains, err := ParseRequestChains(lab)
+	if err != nil {
+		t.Fatalf("ParseRequestChains: %v", err)
+	}
+	stylesheet := chains.Roots[0].Children[0]
+	if chains.Source != criticalRequestChainsAuditID || stylesheet.StartTime == nil || *stylesheet.StartTime != 600 ||
+		stylesheet.EndTime != 1250 || chains.LongestChain.Duration != 1250 || chains.LongestChain.Depth != 2 {
+		t.Errorf("chains = %+v, stylesheet = %+v", chains, stylesheet)
+	}
+	if text := chains.Text(); !strings.Contains(text, "└─ https://example.test/a.css (600-1250 ms, 50 B) *") {
+		t.Errorf("text tree = %s", text)
+	}
+}
+
+func TestParseRequestChains_MissingAudits(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseRequestChains(&LabData{}); err == nil {
+		t.Fatal("ParseRequestChains returned nil error")
+	}
+}
+
+func TestMermaidLabel_EscapesQuotes(t *testing.T) {
+	t.Parallel()
+
+	if got := mermaidLabel(`https://example.test/a"b<c>`); got != "example.test/a#quot;b#lt;c#gt;" {
+		t.Errorf("mermaidLabel = %q", got)
+	}
+}
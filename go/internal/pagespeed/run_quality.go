@@ -0,0 +1,80 @@
+package pagespeed
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// slowHostBenchmarkIndex is the benchmark index below which Lighthouse
+	// considers the host CPU slower than its throttling calibration expects.
+	slowHostBenchmarkIndex = 1000
+	// longRunThresholdMs is the total run time beyond which a PSI run is
+	// unusually long, which usually means the page hit load timeouts.
+	longRunThresholdMs = 30000
+)
+
+// Run quality warning codes.
+const (
+	RunQualitySlowHost            = "slow_host_cpu"
+	RunQualityLongRun             = "long_run"
+	RunQualityLighthouseWarnings  = "lighthouse_warnings"
+	RunQualityRuntimeError        = "runtime_error"
+	RunQualityFormFactorMismatch  = "form_factor_mismatch"
+	RunQualityUnsimulatedThrottle = "unsimulated_throttling"
+)
+
+// RunQualityWarning flags a condition that makes a lab result noisier or
+// less comparable with other runs.
+type RunQualityWarning struct {
+	// Code is a stable warning identifier.
+	Code string `json:"code"`
+	// Message explains the warning and its effect on the result.
+	Message string `json:"message"`
+}
+
+// assessRunQuality derives run-quality warnings from the Lighthouse run
+// metadata.
+func assessRunQuality(metadata AnalysisMetadata) []RunQualityWarning {
+	var warnings []RunQualityWarning
+	add := func(code, format string, args ...any) {
+		warnings = append(warnings, RunQualityWarning{Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if metadata.RuntimeError != nil && metadata.RuntimeError.Code != "" {
+		add(RunQualityRuntimeError,
+			"Lighthouse reported runtime error %s; lab metrics may be missing or invalid.",
+			metadata.RuntimeError.Code)
+	}
+	if environment := metadata.Environment; environment != nil && environment.BenchmarkIndex != nil &&
+		*environment.BenchmarkIndex < slowHostBenchmarkIndex {
+		add(RunQualitySlowHost,
+			"The host CPU benchmark index is %.0f, below %d, so CPU-bound metrics such as TBT may be inflated.",
+			*environment.BenchmarkIndex, slowHostBenchmarkIndex)
+	}
+	if metadata.TotalTimeMs != nil && *metadata.TotalTimeMs > longRunThresholdMs {
+		add(RunQualityLongRun,
+			"Lighthouse ran for %.1f s, longer than %d s, which often means the page was slow to load or hit timeouts.",
+			*metadata.TotalTimeMs/1000, longRunThresholdMs/1000)
+	}
+	if settings := metadata.ConfigSettings; settings != nil {
+		if settings.FormFactor != "" && metadata.Strategy != "" && !strings.EqualFold(settings.FormFactor, metadata.Strategy) {
+			add(RunQualityFormFactorMismatch,
+				"Lighthouse emulated a %s device for the %s strategy.",
+				settings.FormFactor, metadata.Strategy)
+		}
+		switch strings.ToLower(settings.ThrottlingMethod) {
+		case "devtools":
+			add(RunQualityUnsimulatedThrottle,
+				"Lighthouse applied devtools throttling, which varies more between runs than simulated throttling.")
+		case "provided":
+			add(RunQualityUnsimulatedThrottle,
+				"Lighthouse applied no throttling, so metrics reflect the host network and CPU.")
+		}
+	}
+	if len(metadata.RunWarnings) > 0 {
+		add(RunQualityLighthouseWarnings,
+			"Lighthouse reported %d run warning(s); see runWarnings.", len(metadata.RunWarnings))
+	}
+	return warnings
+}
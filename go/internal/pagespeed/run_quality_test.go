@@ -0,0 +1,59 @@
+package pagespeed
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssessRunQuality_FlagsNoisyRuns(t *testing.T) {
+	t.Parallel()
+
+	benchmarkIndex, totalTime := 640.0, 52300.0
+	warnings := assessRunQuality(AnalysisMetadata{
+		Strategy:     "mobile",
+		RunWarnings:  []string{"The page loaded too slowly to finish within the time limit."},
+		RuntimeError: &RuntimeError{Code: "NO_FCP", Message: "The page did not paint any content."},
+		ConfigSettings: &ConfigSettings{
+			FormFactor:       "desktop",
+			ThrottlingMethod: "devtools",
+		},
+		Environment: &Environment{BenchmarkIndex: &benchmarkIndex},
+		TotalTimeMs: &totalTime,
+	})
+
+	codes := make([]string, 0, len(warnings))
+	for _, warning := range warnings {
+		codes = append(codes, warning.Code)
+	}
+	want := []string{
+		RunQualityRuntimeError,
+		RunQualitySlowHost,
+		RunQualityLongRun,
+		RunQualityFormFactorMismatch,
+		RunQualityUnsimulatedThrottle,
+		RunQualityLighthouseWarnings,
+	}
+	if !reflect.DeepEqual(codes, want) {
+		t.Fatalf("codes = %v, want %v", codes, want)
+	}
+	if message := warnings[2].Message; message != "Lighthouse ran for 52.3 s, longer than 30 s, which often means the page was slow to load or hit timeouts." {
+		t.Errorf("long run message = %q", message)
+	}
+}
+
+func TestAssessRunQuality_CleanRunHasNoWarnings(t *testing.T) {
+	t.Parallel()
+
+	benchmarkIndex, totalTime := 1800.0, 12000.0
+	warnings := assessRunQuality(AnalysisMetadata{
+		Strategy:       "desktop",
+		RunWarnings:    []string{},
+		RuntimeError:   &RuntimeError{},
+		ConfigSettings: &ConfigSettings{FormFactor: "desktop", ThrottlingMethod: "simulate"},
+		Environment:    &Environment{BenchmarkIndex: &benchmarkIndex},
+		TotalTimeMs:    &totalTime,
+	})
+	if len(warnings) != 0 {
+		t.Errorf("warnings = %+v, want none", warnings)
+	}
+}
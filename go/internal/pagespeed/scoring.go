@@ -0,0 +1,203 @@
+package pagespeed
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// inverseErfcOneFifth is erfc^-1(0.2), which places p10 at a score of 0.9.
+const inverseErfcOneFifth = 0.9061938024368232
+
+// ScoringCurve contains a Lighthouse log-normal metric curve and its weight in
+// the performance category.
+type ScoringCurve struct {
+	// P10 is the value that scores 0.9.
+	P10 float64 `json:"p10"`
+	// Median is the value that scores 0.5.
+	Median float64 `json:"median"`
+	// Weight is the metric's weight in the performance score, out of 100.
+	Weight float64 `json:"weight"`
+}
+
+// scoringCurves contains Lighthouse 10+ performance scoring, keyed by strategy
+// and lab metric name.
+var scoringCurves = map[string]map[string]ScoringCurve{
+	"mobile": {
+		"fcp":        {P10: 1800, Median: 3000, Weight: 10},
+		"speedIndex": {P10: 3387, Median: 5800, Weight: 10},
+		"lcp":        {P10: 2500, Median: 4000, Weight: 25},
+		"tbt":        {P10: 200, Median: 600, Weight: 30},
+		"cls":        {P10: 0.1, Median: 0.25, Weight: 25},
+	},
+	"desktop": {
+		"fcp":        {P10: 934, Median: 1600, Weight: 10},
+		"speedIndex": {P10: 1311, Median: 2300, Weight: 10},
+		"lcp":        {P10: 1200, Median: 2400, Weight: 25},
+		"tbt":        {P10: 150, Median: 350, Weight: 30},
+		"cls":        {P10: 0.1, Median: 0.25, Weight: 25},
+	},
+}
+
+// ScoredMetrics contains the lab metric names that determine the performance
+// score, in Lighthouse report order.
+var ScoredMetrics = []string{"fcp", "speedIndex", "lcp", "tbt", "cls"}
+
+// ScoringCurves returns the performance scoring curves for a strategy.
+func ScoringCurves(strategy string) (map[string]ScoringCurve, error) {
+	curves, ok := scoringCurves[strings.ToLower(strings.TrimSpace(strategy))]
+	if !ok {
+		return nil, fmt.Errorf("strategy must be mobile or desktop")
+	}
+	return curves, nil
+}
+
+// Score returns the Lighthouse metric score for a value, truncated to two
+// decimals like Lighthouse's log-normal audit scores.
+func (c ScoringCurve) Score(value float64) float64 {
+	if value <= 0 {
+		return 1
+	}
+	standardized := math.Log(value/c.Median) * inverseErfcOneFifth / -math.Log(c.P10/c.Median)
+	percentile := (1 - math.Erf(standardized)) / 2
+
+	var score float64
+	switch {
+	case value <= c.P10:
+		score = math.Max(0.9, math.Min(1, percentile))
+	case value <= c.Median:
+		score = math.Max(0.5, math.Min(0.8999999999999999, percentile))
+	default:
+		score = math.Max(0, math.Min(0.49999999999999994, percentile))
+	}
+	// Lighthouse nudges scores above 0.9 toward 1.
+	if score > 0.9 {
+		score += 0.05 * (score - 0.9)
+	}
+	return math.Floor(score*100) / 100
+}
+
+// ScoreSimulation projects the performance score after hypothetical metric changes.
+type ScoreSimulation struct {
+	// Strategy is mobile or desktop.
+	Strategy string `json:"strategy"`
+	// ReportedScore is the performance score PSI returned, when simulating a result.
+	ReportedScore *float64 `json:"reportedScore,omitempty"`
+	// BaselineScore is the score computed from the baseline metric values.
+	BaselineScore float64 `json:"baselineScore"`
+	// ProjectedScore is the score computed from the projected metric values.
+	ProjectedScore float64 `json:"projectedScore"`
+	// ScoreChange is ProjectedScore minus BaselineScore.
+	ScoreChange float64 `json:"scoreChange"`
+	// Metrics contains the per-metric values, scores, and score contributions.
+	Metrics []MetricSimulation `json:"metrics"`
+}
+
+// MetricSimulation contains one metric's baseline and projected contribution.
+type MetricSimulation struct {
+	// Metric is the lab metric name.
+	Metric string `json:"metric"`
+	// Weight is the metric's weight in the performance score, out of 100.
+	Weight float64 `json:"weight"`
+	// BaselineValue is the measured or supplied metric value.
+	BaselineValue float64 `json:"baselineValue"`
+	// ProjectedValue is the value after the hypothetical change.
+	ProjectedValue float64 `json:"projectedValue"`
+	// BaselineScore is the metric score of BaselineValue.
+	BaselineScore float64 `json:"baselineScore"`
+	// ProjectedScore is the metric score of ProjectedValue.
+	ProjectedScore float64 `json:"projectedScore"`
+	// BaselineContribution is the metric's share of the score in points out of 100.
+	BaselineContribution float64 `json:"baselineContribution"`
+	// ProjectedContribution is the projected share of the score in points out of 100.
+	ProjectedContribution float64 `json:"projectedContribution"`
+}
+
+// LabMetricValues returns the raw values of the scored metrics in a result.
+func LabMetricValues(result *AnalysisResult) map[string]float64 {
+	values := make(map[string]float64, len(ScoredMetrics))
+	if result == nil || result.LabData == nil {
+		return values
+	}
+	for _, name := range ScoredMetrics {
+		if metric, ok := result.LabData.Metrics[name]; ok && metric.Value != nil {
+			values[name] = *metric.Value
+		}
+	}
+	return values
+}
+
+// SimulateScore projects the performance score after adding changes to the
+// baseline values and then applying absolute targets. Every scored metric
+// must have a baseline value; projected values are clamped at zero.
+func SimulateScore(
+	strategy string,
+	baseline map[string]float64,
+	changes map[string]float64,
+	targets map[string]float64,
+) (*ScoreSimulation, error) {
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	curves, err := ScoringCurves(strategy)
+	if err != nil {
+		return nil, err
+	}
+	for _, values := range []map[string]float64{baseline, changes, targets} {
+		if err := validateScoredMetricNames(values); err != nil {
+			return nil, err
+		}
+	}
+
+	simulation := &ScoreSimulation{
+		Strategy: strategy,
+		Metrics:  make([]MetricSimulation, 0, len(ScoredMetrics)),
+	}
+	var totalWeight, baselineTotal, projectedTotal float64
+	for _, name := range ScoredMetrics {
+		value, ok := baseline[name]
+		if !ok {
+			return nil, fmt.Errorf("a baseline value for %s is required", name)
+		}
+		projected := value + changes[name]
+		if target, ok := targets[name]; ok {
+			projected = target
+		}
+		projected = math.Max(0, projected)
+
+		curve := curves[name]
+		metric := MetricSimulation{
+			Metric:         name,
+			Weight:         curve.Weight,
+			BaselineValue:  value,
+			ProjectedValue: projected,
+			BaselineScore:  curve.Score(value),
+			ProjectedScore: curve.Score(projected),
+		}
+		metric.BaselineContribution = metric.BaselineScore * curve.Weight
+		metric.ProjectedContribution = metric.ProjectedScore * curve.Weight
+		totalWeight += curve.Weight
+		baselineTotal += metric.BaselineContribution
+		projectedTotal += metric.ProjectedContribution
+		simulation.Metrics = append(simulation.Metrics, metric)
+	}
+
+	simulation.BaselineScore = roundScore(baselineTotal / totalWeight)
+	simulation.ProjectedScore = roundScore(projectedTotal / totalWeight)
+	simulation.ScoreChange = roundScore(simulation.ProjectedScore - simulation.BaselineScore)
+	return simulation, nil
+}
+
+func validateScoredMetricNames(values map[string]float64) error {
+	for name := range values {
+		if _, ok := scoringCurves["mobile"][name]; !ok {
+			valid := append([]string(nil), ScoredMetrics...)
+			sort.Strings(valid)
+			return fmt.Errorf("metric %q is not scored; use one of %s", name, strings.Join(valid, ", "))
+		}
+	}
+	return nil
+}
+
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
@@ -0,0 +1,87 @@
+package pagespeed
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScoringCurve_MatchesLighthouseControlPoints(t *testing.T) {
+	t.Parallel()
+
+	curves, err := ScoringCurves("mobile")
+	if err != nil {
+		t.Fatalf("ScoringCurves: %v", err)
+	}
+	lcp := curves["lcp"]
+	tests := []struct {
+		value float64
+		want  float64
+	}{
+		{value: 0, want: 1},
+		{value: 2500, want: 0.9},
+		{value: 4000, want: 0.5},
+		{value: 1200, want: 1},
+		{value: 2300, want: 0.93},
+		{value: 3100, want: 0.75},
+		{value: 8000, want: 0.02},
+	}
+	for _, test := range tests {
+		if got := lcp.Score(test.value); got != test.want {
+			t.Errorf("LCP score(%v) = %v, want %v", test.value, got, test.want)
+		}
+	}
+	if _, err := ScoringCurves("both"); err == nil {
+		t.Error("ScoringCurves(both) must fail")
+	}
+}
+
+func TestSimulateScore_ProjectsMetricChange(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	simulation, err := SimulateScore(
+		"mobile",
+		LabMetricValues(result),
+		map[string]float64{"lcp": -800},
+		map[string]float64{"cls": 0},
+	)
+	if err != nil {
+		t.Fatalf("SimulateScore: %v", err)
+	}
+	if simulation.ProjectedScore <= simulation.BaselineScore {
+		t.Errorf("simulation = %+v, want an improved score", simulation)
+	}
+	if want := roundScore(simulation.ProjectedScore - simulation.BaselineScore); simulation.ScoreChange != want {
+		t.Errorf("score change = %v, want %v", simulation.ScoreChange, want)
+	}
+
+	var lcp, totalWeight float64
+	for _, metric := range simulation.Metrics {
+		totalWeight += metric.Weight
+		if metric.Metric == "lcp" {
+			lcp = metric.ProjectedValue
+			if metric.ProjectedContribution <= metric.BaselineContribution {
+				t.Errorf("lcp contribution = %v -> %v, want an increase",
+					metric.BaselineContribution, metric.ProjectedContribution)
+			}
+		}
+	}
+	if lcp != 2300 || totalWeight != 100 {
+		t.Errorf("projected LCP = %v, total weight = %v; want 2300 and 100", lcp, totalWeight)
+	}
+}
+
+func TestSimulateScore_RejectsUnknownAndMissingMetrics(t *testing.T) {
+	t.Parallel()
+
+	baseline := map[string]float64{"fcp": 1000, "speedIndex": 2000, "lcp": 2000, "tbt": 100, "cls": 0}
+	if _, err := SimulateScore("desktop", baseline, map[string]float64{"inp": -50}, nil); err == nil ||
+		!strings.Contains(err.Error(), "not scored") {
+		t.Errorf("err = %v, want unknown metric rejection", err)
+	}
+	delete(baseline, "tbt")
+	if _, err := SimulateScore("desktop", baseline, nil, nil); err == nil ||
+		!strings.Contains(err.Error(), "tbt") {
+		t.Errorf("err = %v, want missing tbt rejection", err)
+	}
+}
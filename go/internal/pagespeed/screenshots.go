@@ -0,0 +1,259 @@
+package pagespeed
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png"
+	"math"
+	"strings"
+)
+
+const (
+	finalScreenshotAuditID      = "final-screenshot"
+	screenshotThumbnailsAuditID = "screenshot-thumbnails"
+	// maxFilmstripFrames limits the filmstrip frames selected for image output.
+	maxFilmstripFrames = 4
+	// elementCropQuality is the JPEG quality of cropped element images.
+	elementCropQuality = 85
+)
+
+// Screenshot kinds.
+const (
+	ScreenshotFinal     = "final"
+	ScreenshotFilmstrip = "filmstrip"
+	ScreenshotElement   = "element"
+)
+
+// LabScreenshots contains the screenshots Lighthouse captured during load.
+type LabScreenshots struct {
+	// Final is the last screenshot of the page load.
+	Final *Screenshot `json:"final,omitempty"`
+	// Filmstrip contains up to four distinct, evenly spaced thumbnails.
+	Filmstrip []Screenshot `json:"filmstrip,omitempty"`
+	// FilmstripFrameCount is the number of thumbnails Lighthouse captured.
+	FilmstripFrameCount int `json:"filmstripFrameCount"`
+	// FullPage is the full-page screenshot used to crop elements.
+	FullPage *FullPageScreenshot `json:"fullPage,omitempty"`
+}
+
+// FullPageScreenshot is the Lighthouse full-page screenshot. Its image and
+// element positions are omitted from JSON.
+type FullPageScreenshot struct {
+	// Width is the screenshot width in CSS pixels.
+	Width int `json:"width"`
+	// Height is the screenshot height in CSS pixels.
+	Height int `json:"height"`
+	// MIMEType is the image type, such as image/webp.
+	MIMEType string `json:"mimeType"`
+	// Data contains the decoded image bytes.
+	Data []byte `json:"-"`
+	// Nodes contains element rectangles keyed by Lighthouse element identifier.
+	Nodes map[string]Rect `json:"-"`
+}
+
+// Screenshot is one Lighthouse screenshot. The image bytes are omitted from
+// JSON and returned separately as image content.
+type Screenshot struct {
+	// Kind is final, filmstrip, or element.
+	Kind string `json:"kind"`
+	// Timing is the capture time in milliseconds after navigation start.
+	Timing float64 `json:"timing"`
+	// MIMEType is the image type, such as image/jpeg.
+	MIMEType string `json:"mimeType"`
+	// Data contains the decoded image bytes.
+	Data []byte `json:"-"`
+}
+
+// Images returns the final screenshot followed by the selected filmstrip frames.
+func (s *LabScreenshots) Images() []Screenshot {
+	if s == nil {
+		return nil
+	}
+	images := make([]Screenshot, 0, len(s.Filmstrip)+1)
+	if s.Final != nil {
+		images = append(images, *s.Final)
+	}
+	return append(images, s.Filmstrip...)
+}
+
+// parseScreenshots decodes final-screenshot, screenshot-thumbnails, and the
+// full-page screenshot. It returns nil when none contains a decodable image.
+func parseScreenshots(audits map[string]*rawAudit, fullPage *rawFullPageScreenshot) *LabScreenshots {
+	screenshots := &LabScreenshots{}
+	if fullPage != nil {
+		if screenshot, err := decodeScreenshot("", 0, fullPage.Screenshot.Data); err == nil {
+			screenshots.FullPage = &FullPageScreenshot{
+				Width:    fullPage.Screenshot.Width,
+				Height:   fullPage.Screenshot.Height,
+				MIMEType: screenshot.MIMEType,
+				Data:     screenshot.Data,
+				Nodes:    fullPage.Nodes,
+			}
+		}
+	}
+	if audit := audits[finalScreenshotAuditID]; audit != nil {
+		if details, err := DecodeDetails(audit.Details); err == nil {
+			if final, ok := details.(*ScreenshotDetails); ok {
+				if screenshot, err := decodeScreenshot(ScreenshotFinal, final.Timing, final.Data); err == nil {
+					screenshots.Final = &screenshot
+				}
+			}
+		}
+	}
+	if audit := audits[screenshotThumbnailsAuditID]; audit != nil {
+		if details, err := DecodeDetails(audit.Details); err == nil {
+			if filmstrip, ok := details.(*FilmstripDetails); ok {
+				screenshots.FilmstripFrameCount = len(filmstrip.Items)
+				frames := make([]Screenshot, 0, len(filmstrip.Items))
+				for _, item := range filmstrip.Items {
+					frame, err := decodeScreenshot(ScreenshotFilmstrip, item.Timing, item.Data)
+					if err != nil {
+						continue
+					}
+					if len(frames) > 0 && bytes.Equal(frames[len(frames)-1].Data, frame.Data) {
+						continue
+					}
+					frames = append(frames, frame)
+				}
+				screenshots.Filmstrip = selectFrames(frames, maxFilmstripFrames)
+			}
+		}
+	}
+	if screenshots.Final == nil && len(screenshots.Filmstrip) == 0 && screenshots.FullPage == nil {
+		return nil
+	}
+	return screenshots
+}
+
+// decodeScreenshot decodes a base64 data URL such as data:image/jpeg;base64,....
+func decodeScreenshot(kind string, timing float64, dataURL string) (Screenshot, error) {
+	header, encoded, ok := strings.Cut(dataURL, ",")
+	if !ok || !strings.HasPrefix(header, "data:") || !strings.HasSuffix(header, ";base64") {
+		return Screenshot{}, fmt.Errorf("screenshot is not a base64 data URL")
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return Screenshot{}, fmt.Errorf("decode screenshot: %w", err)
+	}
+	return Screenshot{
+		Kind:     kind,
+		Timing:   timing,
+		MIMEType: strings.TrimSuffix(strings.TrimPrefix(header, "data:"), ";base64"),
+		Data:     data,
+	}, nil
+}
+
+// selectFrames picks up to limit evenly spaced frames, keeping the first and last.
+func selectFrames(frames []Screenshot, limit int) []Screenshot {
+	if len(frames) <= limit {
+		return frames
+	}
+	selected := make([]Screenshot, 0, limit)
+	for index := 0; index < limit; index++ {
+		selected = append(selected, frames[index*(len(frames)-1)/(limit-1)])
+	}
+	return selected
+}
+
+// CropElement crops an element out of the full-page screenshot and encodes
+// it as JPEG. When the full-page image cannot be decoded, as with WebP, it
+// crops the final screenshot instead if the element lies within the initial
+// viewport.
+func (s *LabScreenshots) CropElement(node NodeValue) (*Screenshot, error) {
+	if s == nil || s.FullPage == nil || s.FullPage.Width <= 0 {
+		return nil, fmt.Errorf("no full-page screenshot is available")
+	}
+	rect, ok := s.FullPage.Nodes[node.LHID]
+	if !ok {
+		if node.BoundingRect == nil {
+			return nil, fmt.Errorf("element %s has no position", node.Selector)
+		}
+		rect = *node.BoundingRect
+	}
+	if rect.Width <= 0 || rect.Height <= 0 {
+		return nil, fmt.Errorf("element %s has no visible area", node.Selector)
+	}
+
+	if source, _, err := image.Decode(bytes.NewReader(s.FullPage.Data)); err == nil {
+		return cropImage(source, rect, float64(source.Bounds().Dx())/float64(s.FullPage.Width))
+	}
+	if s.Final == nil {
+		return nil, fmt.Errorf("full-page screenshot format %s cannot be decoded", s.FullPage.MIMEType)
+	}
+	source, _, err := image.Decode(bytes.NewReader(s.Final.Data))
+	if err != nil {
+		return nil, fmt.Errorf("decode final screenshot: %w", err)
+	}
+	scale := float64(source.Bounds().Dx()) / float64(s.FullPage.Width)
+	if rect.Bottom*scale > float64(source.Bounds().Dy()) {
+		return nil, fmt.Errorf(
+			"full-page screenshot format %s cannot be decoded and element %s is below the initial viewport",
+			s.FullPage.MIMEType,
+			node.Selector,
+		)
+	}
+	return cropImage(source, rect, scale)
+}
+
+func cropImage(source image.Image, rect Rect, scale float64) (*Screenshot, error) {
+	bounds := image.Rect(
+		int(math.Floor(rect.Left*scale)),
+		int(math.Floor(rect.Top*scale)),
+		int(math.Ceil(rect.Right*scale)),
+		int(math.Ceil(rect.Bottom*scale)),
+	).Add(source.Bounds().Min).Intersect(source.Bounds())
+	if bounds.Empty() {
+		return nil, fmt.Errorf("element lies outside the screenshot")
+	}
+
+	cropped := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(cropped, cropped.Bounds(), source, bounds.Min, draw.Src)
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, cropped, &jpeg.Options{Quality: elementCropQuality}); err != nil {
+		return nil, fmt.Errorf("encode element crop: %w", err)
+	}
+	return &Screenshot{Kind: ScreenshotElement, MIMEType: "image/jpeg", Data: encoded.Bytes()}, nil
+}
+
+// StripScreenshotData removes base64 image data from screenshot and
+// filmstrip audit details, leaving their timings in place.
+func (d *LabData) StripScreenshotData() {
+	if d == nil {
+		return
+	}
+	for _, audits := range [][]LighthouseAudit{d.Insights, d.Diagnostics, d.UnscoredAudits} {
+		for index := range audits {
+			audits[index].Details = stripImageData(audits[index].Details)
+		}
+	}
+}
+
+func stripImageData(raw json.RawMessage) json.RawMessage {
+	var details map[string]any
+	if len(raw) == 0 || json.Unmarshal(raw, &details) != nil {
+		return raw
+	}
+	switch details["type"] {
+	case DetailsTypeScreenshot:
+		delete(details, "data")
+	case DetailsTypeFilmstrip:
+		items, _ := details["items"].([]any)
+		for _, item := range items {
+			if frame, ok := item.(map[string]any); ok {
+				delete(frame, "data")
+			}
+		}
+	default:
+		return raw
+	}
+	stripped, err := json.Marshal(details)
+	if err != nil {
+		return raw
+	}
+	return stripped
+}
@@ -0,0 +1,58 @@
+package pagespeed
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseScreenshots_DecodesFinalAndFilmstrip(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	screenshots := result.LabData.Screenshots
+	if screenshots == nil || screenshots.Final == nil {
+		t.Fatal("final screenshot must be decoded")
+	}
+	if screenshots.Final.MIMEType != "image/jpeg" || screenshots.Final.Timing != 3100 ||
+		len(screenshots.Final.Data) == 0 {
+		t.Errorf("final = %+v", screenshots.Final)
+	}
+	if screenshots.FilmstripFrameCount != 2 || len(screenshots.Filmstrip) != 2 {
+		t.Errorf("filmstrip = %d of %d frames, want 2 of 2",
+			len(screenshots.Filmstrip), screenshots.FilmstripFrameCount)
+	}
+	if images := screenshots.Images(); len(images) != 3 || images[0].Kind != ScreenshotFinal {
+		t.Errorf("images = %d, want the final screenshot first", len(images))
+	}
+
+	result.LabData.StripScreenshotData()
+	for _, audit := range result.LabData.UnscoredAudits {
+		if strings.Contains(string(audit.Details), "base64") {
+			t.Errorf("%s details still contain image data", audit.ID)
+		}
+	}
+	if audit := findAudit(t, result.LabData.UnscoredAudits, "screenshot-thumbnails"); !strings.Contains(
+		string(audit.Details), `"timing":3000`) {
+		t.Errorf("filmstrip details = %s, want timings preserved", audit.Details)
+	}
+}
+
+func TestSelectFrames_KeepsFirstAndLastEvenlySpaced(t *testing.T) {
+	t.Parallel()
+
+	frames := make([]Screenshot, 10)
+	for index := range frames {
+		frames[index].Timing = float64(index * 100)
+	}
+	selected := selectFrames(frames, maxFilmstripFrames)
+	var timings []float64
+	for _, frame := range selected {
+		timings = append(timings, frame.Timing)
+	}
+	if len(timings) != 4 || timings[0] != 0 || timings[1] != 300 || timings[2] != 600 || timings[3] != 900 {
+		t.Errorf("timings = %v, want [0 300 600 900]", timings)
+	}
+	if _, err := decodeScreenshot(ScreenshotFinal, 0, "https://example.test/image.jpg"); err == nil {
+		t.Error("a non-data URL must be rejected")
+	}
+}
@@ -0,0 +1,58 @@
+package pagespeed
+
+// StackPack is a framework or platform Lighthouse detected on the page, such
+// as WordPress, React, or Next.js.
+type StackPack struct {
+	// ID is the stack pack identifier, such as wordpress or next.js.
+	ID string `json:"id"`
+	// Title is the stack's display name.
+	Title string `json:"title"`
+}
+
+// StackAdvice is remediation advice for one audit specific to a detected stack.
+type StackAdvice struct {
+	// Stack is the stack pack identifier.
+	Stack string `json:"stack"`
+	// Title is the stack's display name.
+	Title string `json:"title"`
+	// Advice is the stack-specific recommendation in Lighthouse markdown.
+	Advice string `json:"advice"`
+}
+
+type rawStackPack struct {
+	ID           string            `json:"id"`
+	Title        string            `json:"title"`
+	Descriptions map[string]string `json:"descriptions"`
+}
+
+// parseStackPacks returns the detected stacks in Lighthouse order.
+func parseStackPacks(packs []rawStackPack) []StackPack {
+	if len(packs) == 0 {
+		return nil
+	}
+	stacks := make([]StackPack, 0, len(packs))
+	for _, pack := range packs {
+		stacks = append(stacks, StackPack{ID: pack.ID, Title: pack.Title})
+	}
+	return stacks
+}
+
+// applyStackAdvice attaches each stack pack's advice to the insight,
+// diagnostic, and unscored audits it describes.
+func applyStackAdvice(data *LabData, packs []rawStackPack) {
+	for _, audits := range [][]LighthouseAudit{data.Insights, data.Diagnostics, data.UnscoredAudits} {
+		for index := range audits {
+			for _, pack := range packs {
+				advice, ok := pack.Descriptions[audits[index].ID]
+				if !ok || advice == "" {
+					continue
+				}
+				audits[index].StackAdvice = append(audits[index].StackAdvice, StackAdvice{
+					Stack:  pack.ID,
+					Title:  pack.Title,
+					Advice: advice,
+				})
+			}
+		}
+	}
+}
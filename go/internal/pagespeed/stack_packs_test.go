@@ -0,0 +1,42 @@
+package pagespeed
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseResult_StackPacksAttachAdviceToAudits(t *testing.T) {
+	t.Parallel()
+
+	lab := parseResult("https://example.test/page", "mobile", loadPSIFixture(t)).LabData
+
+	wantStacks := []StackPack{{ID: "react", Title: "React"}, {ID: "next.js", Title: "Next.js"}}
+	if !reflect.DeepEqual(lab.StackPacks, wantStacks) {
+		t.Errorf("stack packs = %+v, want %+v", lab.StackPacks, wantStacks)
+	}
+
+	renderBlocking := findAudit(t, lab.Insights, "render-blocking-insight")
+	if len(renderBlocking.StackAdvice) != 2 || renderBlocking.StackAdvice[0].Stack != "react" ||
+		renderBlocking.StackAdvice[1].Title != "Next.js" ||
+		renderBlocking.StackAdvice[1].Advice != "Use the `next/script` component to defer loading of non-critical third-party scripts." {
+		t.Errorf("render-blocking stack advice = %+v", renderBlocking.StackAdvice)
+	}
+	compression := findAudit(t, lab.Diagnostics, "uses-text-compression")
+	if len(compression.StackAdvice) != 1 || compression.StackAdvice[0].Stack != "next.js" {
+		t.Errorf("text compression stack advice = %+v", compression.StackAdvice)
+	}
+	if advice := findAudit(t, lab.UnscoredAudits, "long-tasks").StackAdvice; advice != nil {
+		t.Errorf("long-tasks stack advice = %+v, want none", advice)
+	}
+}
+
+func TestParseResult_WithoutStackPacks_OmitsStacks(t *testing.T) {
+	t.Parallel()
+
+	lab := parseResult("https://example.test", "mobile", &apiResponse{
+		LighthouseResult: &rawLighthouseResult{},
+	}).LabData
+	if lab.StackPacks != nil {
+		t.Errorf("stack packs = %+v, want nil", lab.StackPacks)
+	}
+}
@@ -0,0 +1,285 @@
+package pagespeed
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	bootupTimeAuditID = "bootup-time"
+	longTasksAuditID  = "long-tasks"
+	// longTaskThreshold is the task duration in milliseconds beyond which a
+	// main-thread task counts as blocking.
+	longTaskThreshold = 50
+)
+
+// thirdPartySummaryAuditIDs lists the audits that summarize third-party cost
+// per entity, in order of preference. third-party-summary predates
+// Lighthouse 13.
+var thirdPartySummaryAuditIDs = []string{
+	"third-parties-insight",
+	"third-party-summary",
+}
+
+// ThirdPartyReport contains the cost of each entity that served resources
+// during load. Sizes are in bytes and times are in milliseconds.
+type ThirdPartyReport struct {
+	// Entities lists every entity, highest main-thread time first.
+	Entities []EntityImpact `json:"entities"`
+	// ThirdParty totals the entities that are not first party.
+	ThirdParty EntityTotals `json:"thirdParty"`
+	// FirstParty totals the first-party entities.
+	FirstParty EntityTotals `json:"firstParty"`
+	// Sources lists the audits the report was built from.
+	Sources []string `json:"sources"`
+}
+
+// EntityImpact is the cost of one Lighthouse entity.
+type EntityImpact struct {
+	// Name is the entity name, or the origin of resources with no entity.
+	Name string `json:"name"`
+	// Category is the entity category, such as analytics, ad, or tag-manager.
+	Category string `json:"category,omitempty"`
+	// Homepage is the entity homepage.
+	Homepage string `json:"homepage,omitempty"`
+	// IsFirstParty reports whether Lighthouse classified the entity as first party.
+	IsFirstParty bool `json:"isFirstParty"`
+	// Requests is the number of network requests.
+	Requests int `json:"requests"`
+	// TransferSize is the encoded size transferred over the network.
+	TransferSize float64 `json:"transferSize"`
+	// MainThreadTime is the main-thread time attributed to the entity's URLs.
+	MainThreadTime float64 `json:"mainThreadTime"`
+	// ScriptingTime is the script evaluation, parse, and compile time.
+	ScriptingTime float64 `json:"scriptingTime"`
+	// BlockingTime is the time the entity's long tasks spent beyond 50 ms.
+	BlockingTime float64 `json:"blockingTime"`
+	// Pages is the number of reports that included the entity when reports
+	// are aggregated.
+	Pages int `json:"pages,omitempty"`
+}
+
+// EntityTotals sums the impact of a set of entities.
+type EntityTotals struct {
+	// Entities is the number of entities.
+	Entities int `json:"entities"`
+	// Requests is the number of network requests.
+	Requests int `json:"requests"`
+	// TransferSize is the total transfer size.
+	TransferSize float64 `json:"transferSize"`
+	// TransferShare is TransferSize divided by the transfer size of every entity.
+	TransferShare float64 `json:"transferShare"`
+	// MainThreadTime is the total main-thread time.
+	MainThreadTime float64 `json:"mainThreadTime"`
+	// BlockingTime is the total blocking time.
+	BlockingTime float64 `json:"blockingTime"`
+}
+
+// ParseThirdPartyReport joins LabData.Entities with the network-requests,
+// third-party summary, bootup-time, and long-tasks audits. Main-thread time
+// comes from the third-party summary when it lists the entity, and otherwise
+// from bootup-time. Blocking time comes from the legacy third-party-summary
+// when present, and otherwise from long tasks. It fails when none of the
+// audits is present.
+func ParseThirdPartyReport(lab *LabData) (*ThirdPartyReport, error) {
+	report := &ThirdPartyReport{Sources: make([]string, 0, 4)}
+	impacts := make(map[string]*EntityImpact, len(lab.Entities))
+	for _, entity := range lab.Entities {
+		impacts[entity.Name] = &EntityImpact{
+			Name:         entity.Name,
+			Category:     entity.Category,
+			Homepage:     entity.Homepage,
+			IsFirstParty: entity.IsFirstParty,
+		}
+	}
+	impactFor := func(item TableItem) *EntityImpact {
+		name, _ := item.Text("entity")
+		if name == "" {
+			rawURL, _ := item.Text("url")
+			if entity := EntityForURL(lab.Entities, rawURL); entity != nil {
+				name = entity.Name
+			} else if origin := requestOrigin(rawURL); origin != "Other" {
+				name = origin
+			} else {
+				return nil
+			}
+		}
+		impact, ok := impacts[name]
+		if !ok {
+			impact = &EntityImpact{Name: name}
+			impacts[name] = impact
+		}
+		return impact
+	}
+
+	networkTransfer := make(map[*EntityImpact]float64)
+	if table := auditTable(lab, networkRequestsAuditID); table != nil {
+		report.Sources = append(report.Sources, networkRequestsAuditID)
+		for _, item := range table.Items {
+			if impact := impactFor(item); impact != nil {
+				transferSize, _ := item.Number("transferSize")
+				impact.Requests++
+				networkTransfer[impact] += transferSize
+			}
+		}
+	}
+
+	summarized := make(map[*EntityImpact]bool)
+	hasBlockingTime := false
+	for _, id := range thirdPartySummaryAuditIDs {
+		table := auditTable(lab, id)
+		if table == nil {
+			continue
+		}
+		report.Sources = append(report.Sources, id)
+		for _, item := range table.Items {
+			impact := impactFor(item)
+			if impact == nil {
+				continue
+			}
+			summarized[impact] = true
+			if transferSize, ok := item.Number("transferSize"); ok {
+				impact.TransferSize = transferSize
+			}
+			impact.MainThreadTime, _ = item.Number("mainThreadTime")
+			if blockingTime, ok := item.Number("blockingTime"); ok {
+				impact.BlockingTime = blockingTime
+				hasBlockingTime = true
+			}
+		}
+		break
+	}
+	for impact, transferSize := range networkTransfer {
+		if !summarized[impact] {
+			impact.TransferSize = transferSize
+		}
+	}
+
+	if table := auditTable(lab, bootupTimeAuditID); table != nil {
+		report.Sources = append(report.Sources, bootupTimeAuditID)
+		for _, item := range table.Items {
+			impact := impactFor(item)
+			if impact == nil {
+				continue
+			}
+			scripting, _ := item.Number("scripting")
+			parseCompile, _ := item.Number("scriptParseCompile")
+			impact.ScriptingTime += scripting + parseCompile
+			if !summarized[impact] {
+				total, _ := item.Number("total")
+				impact.MainThreadTime += total
+			}
+		}
+	}
+
+	if table := auditTable(lab, longTasksAuditID); table != nil && !hasBlockingTime {
+		report.Sources = append(report.Sources, longTasksAuditID)
+		for _, item := range table.Items {
+			impact := impactFor(item)
+			duration, _ := item.Number("duration")
+			if impact != nil && duration > longTaskThreshold {
+				impact.BlockingTime += duration - longTaskThreshold
+			}
+		}
+	}
+
+	if len(report.Sources) == 0 {
+		return nil, fmt.Errorf("lighthouse result has no network, third-party, or main-thread audits")
+	}
+	report.Entities = sortEntityImpacts(impacts)
+	report.FirstParty, report.ThirdParty = totalEntityImpacts(report.Entities)
+	return report, nil
+}
+
+// AggregateThirdPartyReports sums per-entity costs across reports, such as
+// the pages of a batch. Each entity's Pages counts the reports it appeared in.
+func AggregateThirdPartyReports(reports []*ThirdPartyReport) *ThirdPartyReport {
+	aggregate := &ThirdPartyReport{Sources: make([]string, 0)}
+	impacts := make(map[string]*EntityImpact)
+	sources := make(map[string]struct{})
+	for _, report := range reports {
+		if report == nil {
+			continue
+		}
+		for _, source := range report.Sources {
+			if _, ok := sources[source]; !ok {
+				sources[source] = struct{}{}
+				aggregate.Sources = append(aggregate.Sources, source)
+			}
+		}
+		for _, entity := range report.Entities {
+			impact, ok := impacts[entity.Name]
+			if !ok {
+				impact = &EntityImpact{
+					Name:         entity.Name,
+					Category:     entity.Category,
+					Homepage:     entity.Homepage,
+					IsFirstParty: entity.IsFirstParty,
+				}
+				impacts[entity.Name] = impact
+			}
+			impact.Requests += entity.Requests
+			impact.TransferSize += entity.TransferSize
+			impact.MainThreadTime += entity.MainThreadTime
+			impact.ScriptingTime += entity.ScriptingTime
+			impact.BlockingTime += entity.BlockingTime
+			impact.Pages += max(1, entity.Pages)
+		}
+	}
+	aggregate.Entities = sortEntityImpacts(impacts)
+	aggregate.FirstParty, aggregate.ThirdParty = totalEntityImpacts(aggregate.Entities)
+	return aggregate
+}
+
+func auditTable(lab *LabData, id string) *TableDetails {
+	audit, ok := lab.Audit(id)
+	if !ok {
+		return nil
+	}
+	details, err := audit.DecodeDetails()
+	if err != nil {
+		return nil
+	}
+	table, _ := details.(*TableDetails)
+	return table
+}
+
+func sortEntityImpacts(impacts map[string]*EntityImpact) []EntityImpact {
+	sorted := make([]EntityImpact, 0, len(impacts))
+	for _, impact := range impacts {
+		if impact.Requests == 0 && impact.TransferSize == 0 && impact.MainThreadTime == 0 &&
+			impact.ScriptingTime == 0 && impact.BlockingTime == 0 {
+			continue
+		}
+		sorted = append(sorted, *impact)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].MainThreadTime != sorted[j].MainThreadTime {
+			return sorted[i].MainThreadTime > sorted[j].MainThreadTime
+		}
+		if sorted[i].TransferSize != sorted[j].TransferSize {
+			return sorted[i].TransferSize > sorted[j].TransferSize
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func totalEntityImpacts(impacts []EntityImpact) (firstParty, thirdParty EntityTotals) {
+	var totalTransfer float64
+	for _, impact := range impacts {
+		totals := &thirdParty
+		if impact.IsFirstParty {
+			totals = &firstParty
+		}
+		totals.Entities++
+		totals.Requests += impact.Requests
+		totals.TransferSize += impact.TransferSize
+		totals.MainThreadTime += impact.MainThreadTime
+		totals.BlockingTime += impact.BlockingTime
+		totalTransfer += impact.TransferSize
+	}
+	firstParty.TransferShare = byteRatio(firstParty.TransferSize, totalTransfer)
+	thirdParty.TransferShare = byteRatio(thirdParty.TransferSize, totalTransfer)
+	return firstParty, thirdParty
+}
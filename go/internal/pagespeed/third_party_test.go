@@ -0,0 +1,129 @@
+package pagespeed
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseThirdPartyReport_JoinsEntitiesWithAudits(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	report, err := ParseThirdPartyReport(result.LabData)
+	if err != nil {
+		t.Fatalf("ParseThirdPartyReport: %v", err)
+	}
+
+	wantSources := []string{"network-requests", "third-parties-insight", "bootup-time", "long-tasks"}
+	if !reflect.DeepEqual(report.Sources, wantSources) {
+		t.Errorf("sources = %v, want %v", report.Sources, wantSources)
+	}
+	if len(report.Entities) != 2 {
+		t.Fatalf("entities = %+v, want Example and Google Tag Manager", report.Entities)
+	}
+	example := report.Entities[0]
+	if example.Name != "Example" || !example.IsFirstParty || example.Requests != 5 || example.TransferSize != 344000 ||
+		example.MainThreadTime != 900 || example.ScriptingTime != 820 || example.BlockingTime != 310 {
+		t.Errorf("first party = %+v", example)
+	}
+	gtm := report.Entities[1]
+	if gtm.Name != "Google Tag Manager" || gtm.Category != "tag-manager" || gtm.IsFirstParty || gtm.Requests != 1 ||
+		gtm.TransferSize != 34000 || gtm.MainThreadTime != 420 || gtm.ScriptingTime != 340 || gtm.BlockingTime != 130 {
+		t.Errorf("third party = %+v", gtm)
+	}
+	if report.ThirdParty.Entities != 1 || report.ThirdParty.TransferShare != 0.09 || report.ThirdParty.BlockingTime != 130 {
+		t.Errorf("third-party totals = %+v", report.ThirdParty)
+	}
+	if report.FirstParty.Requests != 5 || report.FirstParty.TransferShare != 0.91 {
+		t.Errorf("first-party totals = %+v", report.FirstParty)
+	}
+}
+
+func TestParseThirdPartyReport_ReadsPassingBootupTime(t *testing.T) {
+	t.Parallel()
+
+	raw := loadPSIFixture(t)
+	score := 1.0
+	raw.LighthouseResult.Audits[bootupTimeAuditID].Score = &score
+	result := parseResult("https://example.test/page", "mobile", raw)
+	report, err := ParseThirdPartyReport(result.LabData)
+	if err != nil {
+		t.Fatalf("ParseThirdPartyReport: %v", err)
+	}
+
+	assertContains(t, result.LabData.PassedAuditIDs, bootupTimeAuditID)
+	assertContains(t, report.Sources, bootupTimeAuditID)
+	if example := report.Entities[0]; example.Name != "Example" || example.MainThreadTime != 900 ||
+		example.ScriptingTime != 820 {
+		t.Errorf("first party = %+v", example)
+	}
+}
+
+func TestParseThirdPartyReport_LegacySummaryBlockingTime(t *testing.T) {
+	t.Parallel()
+
+	lab := &LabData{
+		Entities: []Entity{{Name: "Analytics", Category: "analytics", Origins: []string{"https://stats.example.net"}}},
+		UnscoredAudits: []LighthouseAudit{
+			{
+				ID: "third-party-summary",
+				Details: []byte(`{"type":"table","items":[
+					{"entity":"Analytics","transferSize":9000,"mainThreadTime":200,"blockingTime":75}
+				]}`),
+			},
+			{
+				ID: longTasksAuditID,
+				Details: []byte(`{"type":"table","items":[
+					{"url":"https://stats.example.net/a.js","duration":400}
+				]}`),
+			},
+		},
+	}
+	report, err := ParseThirdPartyReport(lab)
+	if err != nil {
+		t.Fatalf("ParseThirdPartyReport: %v", err)
+	}
+	if impact := report.Entities[0]; impact.BlockingTime != 75 || impact.MainThreadTime != 200 || impact.Category != "analytics" {
+		t.Errorf("entity = %+v", impact)
+	}
+	if !reflect.DeepEqual(report.Sources, []string{"third-party-summary"}) {
+		t.Errorf("sources = %v", report.Sources)
+	}
+}
+
+func TestParseThirdPartyReport_MissingAudits(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseThirdPartyReport(&LabData{}); err == nil {
+		t.Fatal("ParseThirdPartyReport returned nil error")
+	}
+}
+
+func TestAggregateThirdPartyReports_SumsAndCountsPages(t *testing.T) {
+	t.Parallel()
+
+	page := &ThirdPartyReport{
+		Sources: []string{"network-requests"},
+		Entities: []EntityImpact{
+			{Name: "Example", IsFirstParty: true, Requests: 3, TransferSize: 1000},
+			{Name: "Ads", Category: "ad", Requests: 2, TransferSize: 500, MainThreadTime: 300, BlockingTime: 40},
+		},
+	}
+	other := &ThirdPartyReport{
+		Sources:  []string{"network-requests", "bootup-time"},
+		Entities: []EntityImpact{{Name: "Ads", Category: "ad", Requests: 1, TransferSize: 500, MainThreadTime: 100}},
+	}
+	aggregate := AggregateThirdPartyReports([]*ThirdPartyReport{page, nil, other})
+
+	if !reflect.DeepEqual(aggregate.Sources, []string{"network-requests", "bootup-time"}) {
+		t.Errorf("sources = %v", aggregate.Sources)
+	}
+	ads := aggregate.Entities[0]
+	if ads.Name != "Ads" || ads.Pages != 2 || ads.Requests != 3 || ads.TransferSize != 1000 ||
+		ads.MainThreadTime != 400 || ads.BlockingTime != 40 {
+		t.Errorf("aggregated third party = %+v", ads)
+	}
+	if aggregate.ThirdParty.TransferShare != 0.5 || aggregate.FirstParty.Entities != 1 {
+		t.Errorf("totals = %+v / %+v", aggregate.FirstParty, aggregate.ThirdParty)
+	}
+}
@@ -0,0 +1,313 @@
+package pagespeed
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const scriptTreemapAuditID = "script-treemap-data"
+
+// Script party groups.
+const (
+	PartyFirst   = "first-party"
+	PartyThird   = "third-party"
+	PartyUnknown = "unattributed"
+)
+
+// ScriptTreemap summarizes JavaScript bundle and module sizes from the
+// script-treemap-data audit. Sizes are in bytes.
+type ScriptTreemap struct {
+	// Summary contains totals across every script.
+	Summary ScriptTreemapSummary `json:"summary"`
+	// Bundles contains the largest scripts, largest resource size first.
+	Bundles []ScriptBundle `json:"bundles"`
+	// Modules contains the source-mapped modules with the most unused bytes.
+	Modules []ScriptModule `json:"modules"`
+	// LargestModules contains the largest source-mapped modules, used or not.
+	LargestModules []ScriptModule `json:"largestModules"`
+	// DuplicateModules lists modules included more than once, most wasted bytes first.
+	DuplicateModules []DuplicateModule `json:"duplicateModules"`
+	// ByParty groups scripts into first-party, third-party, and unattributed.
+	ByParty []ScriptGroup `json:"byParty"`
+	// ByEntity groups scripts by Lighthouse entity, largest resource size first.
+	ByEntity []ScriptGroup `json:"byEntity"`
+}
+
+// ScriptTreemapSummary contains script totals.
+type ScriptTreemapSummary struct {
+	// Scripts is the number of scripts in the treemap.
+	Scripts int `json:"scripts"`
+	// ResourceBytes is the total uncompressed script size.
+	ResourceBytes float64 `json:"resourceBytes"`
+	// TransferBytes is the total encoded script size.
+	TransferBytes float64 `json:"transferBytes"`
+	// UnusedBytes is the total size of code not executed during load.
+	UnusedBytes float64 `json:"unusedBytes"`
+	// UnusedRatio is UnusedBytes divided by ResourceBytes.
+	UnusedRatio float64 `json:"unusedRatio"`
+	// DuplicatedBytes is the size of module copies beyond the largest one.
+	DuplicatedBytes float64 `json:"duplicatedBytes"`
+}
+
+// ScriptBundle is one script from the treemap.
+type ScriptBundle struct {
+	// URL is the script URL.
+	URL string `json:"url"`
+	// Entity is the Lighthouse entity that owns the script origin.
+	Entity string `json:"entity,omitempty"`
+	// Party is first-party, third-party, or unattributed.
+	Party string `json:"party"`
+	// ResourceBytes is the uncompressed script size.
+	ResourceBytes float64 `json:"resourceBytes"`
+	// TransferBytes is the encoded script size when Lighthouse reports it.
+	TransferBytes *float64 `json:"transferBytes,omitempty"`
+	// UnusedBytes is the size of code not executed during load.
+	UnusedBytes float64 `json:"unusedBytes"`
+	// UnusedRatio is UnusedBytes divided by ResourceBytes.
+	UnusedRatio float64 `json:"unusedRatio"`
+	// Modules is the number of source-mapped modules in the script.
+	Modules int `json:"modules"`
+}
+
+// ScriptModule is one source-mapped module inside a script.
+type ScriptModule struct {
+	// Bundle is the URL of the script containing the module.
+	Bundle string `json:"bundle"`
+	// Path is the module path within the bundle.
+	Path string `json:"path"`
+	// ResourceBytes is the module size.
+	ResourceBytes float64 `json:"resourceBytes"`
+	// UnusedBytes is the size of module code not executed during load.
+	UnusedBytes float64 `json:"unusedBytes"`
+	// UnusedRatio is UnusedBytes divided by ResourceBytes.
+	UnusedRatio float64 `json:"unusedRatio"`
+}
+
+// DuplicateModule is a module Lighthouse found in more than one place.
+type DuplicateModule struct {
+	// Name is the normalized module name, such as lodash.
+	Name string `json:"name"`
+	// Copies lists every occurrence, largest first.
+	Copies []ScriptModule `json:"copies"`
+	// ResourceBytes is the combined size of every copy.
+	ResourceBytes float64 `json:"resourceBytes"`
+	// WastedBytes is the combined size of every copy except the largest.
+	WastedBytes float64 `json:"wastedBytes"`
+}
+
+// ScriptGroup summarizes the scripts sharing a party or entity.
+type ScriptGroup struct {
+	// Name is the party or entity name.
+	Name string `json:"name"`
+	// IsFirstParty reports whether an entity group is first party.
+	IsFirstParty *bool `json:"isFirstParty,omitempty"`
+	// Scripts is the number of scripts.
+	Scripts int `json:"scripts"`
+	// ResourceBytes is the total uncompressed size.
+	ResourceBytes float64 `json:"resourceBytes"`
+	// UnusedBytes is the total unused size.
+	UnusedBytes float64 `json:"unusedBytes"`
+	// UnusedRatio is UnusedBytes divided by ResourceBytes.
+	UnusedRatio float64 `json:"unusedRatio"`
+}
+
+// ParseScriptTreemap summarizes the script-treemap-data audit, returning at
+// most limit bundles and at most limit modules in each module ranking. A
+// limit of zero or less returns them all. Totals, duplicates, and groups
+// always cover every script.
+func ParseScriptTreemap(lab *LabData, limit int) (*ScriptTreemap, error) {
+	audit, ok := lab.Audit(scriptTreemapAuditID)
+	if !ok {
+		return nil, fmt.Errorf("lighthouse result has no %s audit", scriptTreemapAuditID)
+	}
+	details, err := audit.DecodeDetails()
+	if err != nil {
+		return nil, err
+	}
+	treemap, ok := details.(*TreemapDataDetails)
+	if !ok {
+		return nil, fmt.Errorf("%s details are not treemap data", scriptTreemapAuditID)
+	}
+
+	result := &ScriptTreemap{
+		Bundles:          make([]ScriptBundle, 0, len(treemap.Nodes)),
+		Modules:          make([]ScriptModule, 0),
+		DuplicateModules: make([]DuplicateModule, 0),
+	}
+	duplicates := make(map[string]*DuplicateModule)
+	byParty := make(map[string]*ScriptGroup)
+	byEntity := make(map[string]*ScriptGroup)
+	for _, node := range treemap.Nodes {
+		bundle := ScriptBundle{
+			URL:           node.Name,
+			Party:         PartyUnknown,
+			ResourceBytes: node.ResourceBytes,
+			TransferBytes: node.EncodedBytes,
+			UnusedBytes:   optionalBytes(node.UnusedBytes),
+		}
+		bundle.UnusedRatio = byteRatio(bundle.UnusedBytes, bundle.ResourceBytes)
+		var entity *Entity
+		if entity = EntityForURL(lab.Entities, node.Name); entity != nil {
+			bundle.Entity = entity.Name
+			bundle.Party = PartyThird
+			if entity.IsFirstParty {
+				bundle.Party = PartyFirst
+			}
+		}
+
+		for _, module := range collectModules(node.Name, "", node.Children) {
+			bundle.Modules++
+			result.Modules = append(result.Modules, module.ScriptModule)
+			if module.duplicateName == "" {
+				continue
+			}
+			duplicate, ok := duplicates[module.duplicateName]
+			if !ok {
+				duplicate = &DuplicateModule{Name: module.duplicateName}
+				duplicates[module.duplicateName] = duplicate
+			}
+			duplicate.Copies = append(duplicate.Copies, module.ScriptModule)
+			duplicate.ResourceBytes += module.ResourceBytes
+		}
+
+		result.Summary.Scripts++
+		result.Summary.ResourceBytes += bundle.ResourceBytes
+		result.Summary.TransferBytes += optionalBytes(bundle.TransferBytes)
+		result.Summary.UnusedBytes += bundle.UnusedBytes
+		addScriptGroup(byParty, bundle.Party, nil, bundle)
+		if entity != nil {
+			isFirstParty := entity.IsFirstParty
+			addScriptGroup(byEntity, entity.Name, &isFirstParty, bundle)
+		} else {
+			addScriptGroup(byEntity, requestOrigin(bundle.URL), nil, bundle)
+		}
+		result.Bundles = append(result.Bundles, bundle)
+	}
+	result.Summary.UnusedRatio = byteRatio(result.Summary.UnusedBytes, result.Summary.ResourceBytes)
+
+	for _, duplicate := range duplicates {
+		if len(duplicate.Copies) < 2 {
+			continue
+		}
+		sort.SliceStable(duplicate.Copies, func(i, j int) bool {
+			return duplicate.Copies[i].ResourceBytes > duplicate.Copies[j].ResourceBytes
+		})
+		duplicate.WastedBytes = duplicate.ResourceBytes - duplicate.Copies[0].ResourceBytes
+		result.Summary.DuplicatedBytes += duplicate.WastedBytes
+		result.DuplicateModules = append(result.DuplicateModules, *duplicate)
+	}
+	sort.Slice(result.DuplicateModules, func(i, j int) bool {
+		if result.DuplicateModules[i].WastedBytes != result.DuplicateModules[j].WastedBytes {
+			return result.DuplicateModules[i].WastedBytes > result.DuplicateModules[j].WastedBytes
+		}
+		return result.DuplicateModules[i].Name < result.DuplicateModules[j].Name
+	})
+
+	sort.SliceStable(result.Bundles, func(i, j int) bool {
+		return result.Bundles[i].ResourceBytes > result.Bundles[j].ResourceBytes
+	})
+	result.LargestModules = append([]ScriptModule(nil), result.Modules...)
+	sort.SliceStable(result.LargestModules, func(i, j int) bool {
+		return result.LargestModules[i].ResourceBytes > result.LargestModules[j].ResourceBytes
+	})
+	sort.SliceStable(result.Modules, func(i, j int) bool {
+		if result.Modules[i].UnusedBytes != result.Modules[j].UnusedBytes {
+			return result.Modules[i].UnusedBytes > result.Modules[j].UnusedBytes
+		}
+		return result.Modules[i].ResourceBytes > result.Modules[j].ResourceBytes
+	})
+	if limit > 0 {
+		result.Bundles = result.Bundles[:min(limit, len(result.Bundles))]
+		result.Modules = result.Modules[:min(limit, len(result.Modules))]
+		result.LargestModules = result.LargestModules[:min(limit, len(result.LargestModules))]
+	}
+	result.ByParty = sortScriptGroups(byParty)
+	result.ByEntity = sortScriptGroups(byEntity)
+	return result, nil
+}
+
+// treemapModule is a leaf module with its duplicate name, if any.
+type treemapModule struct {
+	ScriptModule
+	duplicateName string
+}
+
+// collectModules flattens the leaf nodes below a script into modules whose
+// paths join the names of their ancestors.
+func collectModules(bundle, prefix string, nodes []TreemapNode) []treemapModule {
+	var modules []treemapModule
+	for _, node := range nodes {
+		path := strings.TrimPrefix(prefix+"/"+node.Name, "/")
+		if len(node.Children) > 0 {
+			modules = append(modules, collectModules(bundle, path, node.Children)...)
+			continue
+		}
+		module := ScriptModule{
+			Bundle:        bundle,
+			Path:          path,
+			ResourceBytes: node.ResourceBytes,
+			UnusedBytes:   optionalBytes(node.UnusedBytes),
+		}
+		module.UnusedRatio = byteRatio(module.UnusedBytes, module.ResourceBytes)
+		modules = append(modules, treemapModule{
+			ScriptModule:  module,
+			duplicateName: node.DuplicatedNormalizedModuleName,
+		})
+	}
+	return modules
+}
+
+func addScriptGroup(groups map[string]*ScriptGroup, name string, isFirstParty *bool, bundle ScriptBundle) {
+	group, ok := groups[name]
+	if !ok {
+		group = &ScriptGroup{Name: name, IsFirstParty: isFirstParty}
+		groups[name] = group
+	}
+	group.Scripts++
+	group.ResourceBytes += bundle.ResourceBytes
+	group.UnusedBytes += bundle.UnusedBytes
+}
+
+func sortScriptGroups(groups map[string]*ScriptGroup) []ScriptGroup {
+	sorted := make([]ScriptGroup, 0, len(groups))
+	for _, group := range groups {
+		group.UnusedRatio = byteRatio(group.UnusedBytes, group.ResourceBytes)
+		sorted = append(sorted, *group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].ResourceBytes != sorted[j].ResourceBytes {
+			return sorted[i].ResourceBytes > sorted[j].ResourceBytes
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// EntityForURL returns the entity whose origins include the URL's origin, or
+// nil when none does.
+func EntityForURL(entities []Entity, rawURL string) *Entity {
+	origin := requestOrigin(rawURL)
+	for index := range entities {
+		for _, entityOrigin := range entities[index].Origins {
+			if strings.TrimSuffix(entityOrigin, "/") == origin {
+				return &entities[index]
+			}
+		}
+	}
+	return nil
+}
+
+func optionalBytes(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func byteRatio(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return roundScore(part / total)
+}
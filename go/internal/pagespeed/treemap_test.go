@@ -0,0 +1,92 @@
+package pagespeed
+
+import "testing"
+
+func TestParseScriptTreemap_SummarizesFixture(t *testing.T) {
+	t.Parallel()
+
+	result := parseResult("https://example.test/page", "mobile", loadPSIFixture(t))
+	treemap, err := ParseScriptTreemap(result.LabData, 2)
+	if err != nil {
+		t.Fatalf("ParseScriptTreemap: %v", err)
+	}
+
+	summary := treemap.Summary
+	if summary.Scripts != 3 || summary.ResourceBytes != 335000 || summary.TransferBytes != 107000 ||
+		summary.UnusedBytes != 176000 || summary.UnusedRatio != 0.53 || summary.DuplicatedBytes != 52000 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if len(treemap.Bundles) != 2 {
+		t.Fatalf("bundles = %+v, want 2 after limit", treemap.Bundles)
+	}
+	app := treemap.Bundles[0]
+	if app.URL != "https://example.test/app.js" || app.Entity != "Example" || app.Party != PartyFirst ||
+		app.UnusedRatio != 0.5 || app.Modules != 2 {
+		t.Errorf("largest bundle = %+v", app)
+	}
+	if gtm := treemap.Bundles[1]; gtm.Party != PartyThird || gtm.Entity != "Google Tag Manager" {
+		t.Errorf("second bundle = %+v", gtm)
+	}
+
+	if len(treemap.Modules) != 2 {
+		t.Fatalf("modules = %+v, want 2 after limit", treemap.Modules)
+	}
+	if module := treemap.Modules[0]; module.Path != "node_modules/lodash/lodash.js" ||
+		module.Bundle != "https://example.test/app.js" || module.UnusedBytes != 60000 || module.UnusedRatio != 0.86 {
+		t.Errorf("module with most unused bytes = %+v", module)
+	}
+	if len(treemap.LargestModules) != 2 {
+		t.Fatalf("largest modules = %+v, want 2 after limit", treemap.LargestModules)
+	}
+	if module := treemap.LargestModules[0]; module.Path != "src/app.ts" || module.ResourceBytes != 110000 ||
+		module.UnusedRatio != 0.27 {
+		t.Errorf("largest module = %+v, want mostly used src/app.ts", module)
+	}
+
+	if len(treemap.DuplicateModules) != 1 {
+		t.Fatalf("duplicate modules = %+v, want lodash", treemap.DuplicateModules)
+	}
+	lodash := treemap.DuplicateModules[0]
+	if lodash.Name != "lodash" || len(lodash.Copies) != 2 || lodash.ResourceBytes != 122000 ||
+		lodash.WastedBytes != 52000 || lodash.Copies[1].Bundle != "https://example.test/vendor.js" {
+		t.Errorf("lodash duplicate = %+v", lodash)
+	}
+
+	if len(treemap.ByParty) != 2 || treemap.ByParty[0].Name != PartyFirst ||
+		treemap.ByParty[0].Scripts != 2 || treemap.ByParty[0].ResourceBytes != 240000 {
+		t.Errorf("by party = %+v", treemap.ByParty)
+	}
+	if len(treemap.ByEntity) != 2 || treemap.ByEntity[1].Name != "Google Tag Manager" ||
+		treemap.ByEntity[1].IsFirstParty == nil || *treemap.ByEntity[1].IsFirstParty {
+		t.Errorf("by entity = %+v", treemap.ByEntity)
+	}
+}
+
+func TestParseScriptTreemap_UnattributedScriptsGroupByOrigin(t *testing.T) {
+	t.Parallel()
+
+	lab := &LabData{UnscoredAudits: []LighthouseAudit{{
+		ID: scriptTreemapAuditID,
+		Details: []byte(`{"type":"treemap-data","nodes":[
+			{"name":"https://cdn.example.net/widget.js","resourceBytes":1000}
+		]}`),
+	}}}
+	treemap, err := ParseScriptTreemap(lab, 0)
+	if err != nil {
+		t.Fatalf("ParseScriptTreemap: %v", err)
+	}
+	if bundle := treemap.Bundles[0]; bundle.Party != PartyUnknown || bundle.Entity != "" || bundle.UnusedRatio != 0 {
+		t.Errorf("bundle = %+v", bundle)
+	}
+	if group := treemap.ByEntity[0]; group.Name != "https://cdn.example.net" || group.IsFirstParty != nil {
+		t.Errorf("entity group = %+v", group)
+	}
+}
+
+func TestParseScriptTreemap_MissingAudit(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseScriptTreemap(&LabData{}, 10); err == nil {
+		t.Fatal("ParseScriptTreemap returned nil error")
+	}
+}
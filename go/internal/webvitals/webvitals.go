@@ -0,0 +1,128 @@
+// Package webvitals provides Google's web vitals rating thresholds and the
+// Core Web Vitals assessment shared by the CrUX and PageSpeed Insights models.
+package webvitals
+
+// Rating values use the same vocabulary as PSI field data.
+const (
+	RatingGood             = "good"
+	RatingNeedsImprovement = "needs-improvement"
+	RatingPoor             = "poor"
+)
+
+// Assessment status values.
+const (
+	StatusPassed           = "passed"
+	StatusFailed           = "failed"
+	StatusInsufficientData = "insufficient_data"
+)
+
+// Threshold contains Google's rating boundaries for one metric.
+type Threshold struct {
+	// Good is the inclusive upper boundary of the good range.
+	Good float64 `json:"good"`
+	// Poor is the exclusive lower boundary of the poor range.
+	Poor float64 `json:"poor"`
+	// Unit identifies the unit of both boundaries.
+	Unit string `json:"unit,omitempty"`
+}
+
+// Rate returns the rating of value against the threshold.
+func (t Threshold) Rate(value float64) string {
+	switch {
+	case value <= t.Good:
+		return RatingGood
+	case value <= t.Poor:
+		return RatingNeedsImprovement
+	default:
+		return RatingPoor
+	}
+}
+
+// CoreWebVitals contains the short names of the metrics that determine the
+// Core Web Vitals assessment.
+var CoreWebVitals = []string{"lcp", "inp", "cls"}
+
+var thresholds = map[string]Threshold{
+	"lcp":  {Good: 2500, Poor: 4000, Unit: "ms"},
+	"inp":  {Good: 200, Poor: 500, Unit: "ms"},
+	"cls":  {Good: 0.1, Poor: 0.25},
+	"fcp":  {Good: 1800, Poor: 3000, Unit: "ms"},
+	"ttfb": {Good: 800, Poor: 1800, Unit: "ms"},
+}
+
+// ThresholdFor returns the thresholds for a metric short name such as lcp.
+func ThresholdFor(name string) (Threshold, bool) {
+	threshold, ok := thresholds[name]
+	return threshold, ok
+}
+
+// Assessment is a Core Web Vitals verdict computed from p75 values.
+type Assessment struct {
+	// Status is passed, failed, or insufficient_data.
+	Status string `json:"status"`
+	// Passes is the verdict, or null when LCP or CLS is unavailable.
+	Passes *bool `json:"passes"`
+	// Metrics contains the rating of every available Core Web Vital.
+	Metrics map[string]MetricAssessment `json:"metrics"`
+	// FailingMetrics contains the Core Web Vitals whose p75 is not good.
+	FailingMetrics []string `json:"failingMetrics"`
+	// MissingMetrics contains the Core Web Vitals without a p75 value.
+	MissingMetrics []string `json:"missingMetrics"`
+}
+
+// MetricAssessment contains one Core Web Vital's p75 and rating.
+type MetricAssessment struct {
+	// P75 is the assessed 75th-percentile value.
+	P75 float64 `json:"p75"`
+	// Rating is good, needs-improvement, or poor.
+	Rating string `json:"rating"`
+	// Threshold contains the boundaries used for Rating.
+	Threshold Threshold `json:"threshold"`
+}
+
+// Assess computes the Core Web Vitals verdict from p75 values keyed by short
+// metric name. Like PSI, the assessment proceeds without INP but requires LCP
+// and CLS. It returns nil when no Core Web Vital is available.
+func Assess(p75s map[string]*float64) *Assessment {
+	assessment := &Assessment{
+		Metrics:        make(map[string]MetricAssessment, len(CoreWebVitals)),
+		FailingMetrics: []string{},
+		MissingMetrics: []string{},
+	}
+
+	for _, name := range CoreWebVitals {
+		value := p75s[name]
+		if value == nil {
+			assessment.MissingMetrics = append(assessment.MissingMetrics, name)
+			continue
+		}
+		threshold := thresholds[name]
+		rating := threshold.Rate(*value)
+		assessment.Metrics[name] = MetricAssessment{
+			P75:       *value,
+			Rating:    rating,
+			Threshold: threshold,
+		}
+		if rating != RatingGood {
+			assessment.FailingMetrics = append(assessment.FailingMetrics, name)
+		}
+	}
+	if len(assessment.Metrics) == 0 {
+		return nil
+	}
+
+	_, hasLCP := assessment.Metrics["lcp"]
+	_, hasCLS := assessment.Metrics["cls"]
+	if !hasLCP || !hasCLS {
+		assessment.Status = StatusInsufficientData
+		return assessment
+	}
+	passes := len(assessment.FailingMetrics) == 0
+	assessment.Passes = &passes
+	if passes {
+		assessment.Status = StatusPassed
+	} else {
+		assessment.Status = StatusFailed
+	}
+	return assessment
+}
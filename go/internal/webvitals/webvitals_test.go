@@ -0,0 +1,101 @@
+package webvitals
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestThresholdRate_UsesInclusiveGoodAndPoorBoundaries(t *testing.T) {
+	t.Parallel()
+
+	threshold, ok := ThresholdFor("lcp")
+	if !ok {
+		t.Fatal("lcp threshold not found")
+	}
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{value: 2500, want: RatingGood},
+		{value: 2501, want: RatingNeedsImprovement},
+		{value: 4000, want: RatingNeedsImprovement},
+		{value: 4001, want: RatingPoor},
+	}
+	for _, test := range tests {
+		if got := threshold.Rate(test.value); got != test.want {
+			t.Errorf("Rate(%v) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestAssess(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		p75s        map[string]*float64
+		wantStatus  string
+		wantFailing []string
+		wantMissing []string
+	}{
+		{
+			name:        "all good",
+			p75s:        map[string]*float64{"lcp": number(2000), "inp": number(150), "cls": number(0.05)},
+			wantStatus:  StatusPassed,
+			wantFailing: []string{},
+			wantMissing: []string{},
+		},
+		{
+			name:        "inp missing still passes",
+			p75s:        map[string]*float64{"lcp": number(2000), "cls": number(0.05)},
+			wantStatus:  StatusPassed,
+			wantFailing: []string{},
+			wantMissing: []string{"inp"},
+		},
+		{
+			name:        "failing metrics in catalog order",
+			p75s:        map[string]*float64{"lcp": number(4100), "inp": number(150), "cls": number(0.3)},
+			wantStatus:  StatusFailed,
+			wantFailing: []string{"lcp", "cls"},
+			wantMissing: []string{},
+		},
+		{
+			name:        "cls missing is insufficient",
+			p75s:        map[string]*float64{"lcp": number(4100), "cls": nil},
+			wantStatus:  StatusInsufficientData,
+			wantFailing: []string{"lcp"},
+			wantMissing: []string{"inp", "cls"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assessment := Assess(test.p75s)
+			if assessment == nil {
+				t.Fatal("Assess returned nil")
+			}
+			if assessment.Status != test.wantStatus {
+				t.Errorf("status = %q, want %q", assessment.Status, test.wantStatus)
+			}
+			if (assessment.Passes != nil) != (test.wantStatus != StatusInsufficientData) {
+				t.Errorf("passes = %v, want null only for insufficient data", assessment.Passes)
+			}
+			if !reflect.DeepEqual(assessment.FailingMetrics, test.wantFailing) {
+				t.Errorf("failing = %v, want %v", assessment.FailingMetrics, test.wantFailing)
+			}
+			if !reflect.DeepEqual(assessment.MissingMetrics, test.wantMissing) {
+				t.Errorf("missing = %v, want %v", assessment.MissingMetrics, test.wantMissing)
+			}
+		})
+	}
+
+	if Assess(map[string]*float64{"fcp": number(1000)}) != nil {
+		t.Error("Assess must return nil without Core Web Vitals")
+	}
+}
+
+func number(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+// lcpBreakdownInput is the input schema for the analyze_lcp_breakdown tool.
+type lcpBreakdownInput struct {
+	URL      string `json:"url"`
+	Strategy string `json:"strategy,omitempty"`
+	Fallback bool   `json:"fallback,omitempty"`
+}
+
+type lcpBreakdownResponse struct {
+	InputURL       string                     `json:"inputUrl"`
+	Strategy       string                     `json:"strategy"`
+	FieldTarget    string                     `json:"fieldTarget,omitempty"`
+	OriginFallback bool                       `json:"originFallback"`
+	Field          *crux.LCPBreakdown         `json:"field"`
+	Lab            *pagespeed.LabLCPBreakdown `json:"lab"`
+	Comparison     *crux.LCPLabComparison     `json:"comparison"`
+	FieldError     *cruxFailure               `json:"fieldError,omitempty"`
+	LabError       *analysisFailure           `json:"labError,omitempty"`
+}
+
+// strategyFormFactors maps PSI strategies to the matching CrUX form factor.
+var strategyFormFactors = map[string]string{
+	"mobile":  "phone",
+	"desktop": "desktop",
+}
+
+// analyzeLCPBreakdown queries CrUX LCP subparts and a Lighthouse run for the
+// same URL and device class and correlates their dominant bottlenecks.
+func analyzeLCPBreakdown(
+	ctx context.Context,
+	client pageAnalyzer,
+	cruxClient cruxQuerier,
+	input lcpBreakdownInput,
+) (*mcp.CallToolResult, any, error) {
+	strategy := strings.ToLower(strings.TrimSpace(input.Strategy))
+	if strategy == "" {
+		strategy = "mobile"
+	}
+	formFactor, ok := strategyFormFactors[strategy]
+	if !ok {
+		return nil, nil, fmt.Errorf("strategy must be mobile or desktop")
+	}
+
+	metrics := []string{"largest_contentful_paint", "largest_contentful_paint_resource_type"}
+	for _, subpart := range crux.LCPSubparts {
+		metrics = append(metrics, subpart.Metric)
+	}
+	cruxRequest, err := crux.NewQueryRequest(input.URL, "url", formFactor, metrics, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	cruxRequest.OriginFallback = input.Fallback
+	analysisRequest, err := pagespeed.NewAnalysisRequest(
+		input.URL,
+		strategy,
+		[]string{"performance"},
+		"",
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := lcpBreakdownResponse{
+		InputURL: input.URL,
+		Strategy: strategy,
+	}
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(2)
+	go func() {
+		defer waitGroup.Done()
+		result, err := cruxClient.QueryCurrent(ctx, cruxRequest)
+		if err != nil {
+			slog.Warn("CrUX LCP breakdown query failed", "target", cruxRequest.Target, "err", err)
+			failure := classifyCruxFailure(cruxRequest, err)
+			response.FieldError = &failure
+			return
+		}
+		response.FieldTarget = result.Target
+		response.OriginFallback = result.OriginFallback
+		response.Field = result.LCPBreakdown
+	}()
+	go func() {
+		defer waitGroup.Done()
+		result, err := client.Analyze(ctx, analysisRequest)
+		if err != nil {
+			slog.Warn("PSI LCP breakdown analysis failed", "url", analysisRequest.URL, "err", err)
+			failure := classifyAnalysisFailure(analysisRequest, err)
+			response.LabError = &failure
+			return
+		}
+		if result.LabData != nil {
+			response.Lab = result.LabData.LCPBreakdown
+		}
+	}()
+	waitGroup.Wait()
+
+	if ctx.Err() != nil {
+		return nil, nil, ctx.Err()
+	}
+	if response.FieldError != nil && response.LabError != nil {
+		return errorToolResult(response)
+	}
+
+	if response.Lab != nil {
+		response.Comparison = crux.CompareLCPBreakdown(
+			response.Field,
+			response.Lab.LCP,
+			response.Lab.Durations(),
+		)
+	}
+	return jsonToolResult(response)
+}
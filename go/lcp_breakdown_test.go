@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+type breakdownCruxQuerier struct {
+	fakeCruxQuerier
+	formFactor string
+}
+
+func (q *breakdownCruxQuerier) QueryCurrent(
+	_ context.Context,
+	request crux.QueryRequest,
+) (*crux.Result, error) {
+	q.formFactor = request.FormFactor
+	lcp, ttfb, delay := 3000.0, 1500.0, 400.0
+	return &crux.Result{
+		Target:     request.Target,
+		TargetType: request.TargetType,
+		LCPBreakdown: &crux.LCPBreakdown{
+			LCP: &lcp,
+			Subparts: []crux.LCPSubpart{
+				{Name: "timeToFirstByte", P75: &ttfb},
+				{Name: "resourceLoadDelay", P75: &delay},
+			},
+			DominantSubpart: "timeToFirstByte",
+		},
+	}, nil
+}
+
+type breakdownAnalyzer struct {
+	err error
+}
+
+func (a breakdownAnalyzer) Analyze(
+	_ context.Context,
+	request pagespeed.AnalysisRequest,
+) (*pagespeed.AnalysisResult, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	lcp := 2000.0
+	return &pagespeed.AnalysisResult{
+		Metadata: pagespeed.AnalysisMetadata{InputURL: request.URL, Strategy: request.Strategy},
+		LabData: &pagespeed.LabData{
+			LCPBreakdown: &pagespeed.LabLCPBreakdown{
+				LCP: &lcp,
+				Subparts: []pagespeed.LabLCPSubpart{
+					{Name: "timeToFirstByte", Duration: 900},
+					{Name: "resourceLoadDelay", Duration: 300},
+				},
+				DominantSubpart: "timeToFirstByte",
+			},
+		},
+	}, nil
+}
+
+func TestAnalyzeLCPBreakdown_CorrelatesFieldAndLab(t *testing.T) {
+	t.Parallel()
+
+	querier := &breakdownCruxQuerier{}
+	result, _, err := analyzeLCPBreakdown(
+		context.Background(),
+		breakdownAnalyzer{},
+		querier,
+		lcpBreakdownInput{URL: "https://example.test/page"},
+	)
+	if err != nil {
+		t.Fatalf("analyzeLCPBreakdown: %v", err)
+	}
+	if querier.formFactor != "phone" {
+		t.Errorf("CrUX form factor = %q, want phone for the mobile strategy", querier.formFactor)
+	}
+
+	response := decodeToolText[lcpBreakdownResponse](t, result)
+	if response.Comparison == nil || !response.Comparison.DominantSubpartsAgree {
+		t.Fatalf("comparison = %+v, want agreement on timeToFirstByte", response.Comparison)
+	}
+	if response.Comparison.LabDominantSubpart != "timeToFirstByte" {
+		t.Errorf("lab dominant subpart = %q, want timeToFirstByte", response.Comparison.LabDominantSubpart)
+	}
+}
+
+func TestAnalyzeLCPBreakdown_PartialFailureKeepsFieldData(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := analyzeLCPBreakdown(
+		context.Background(),
+		breakdownAnalyzer{err: errors.New("Lighthouse returned error: FAILED_DOCUMENT_REQUEST")},
+		&breakdownCruxQuerier{},
+		lcpBreakdownInput{URL: "https://example.test/page", Strategy: "desktop"},
+	)
+	if err != nil {
+		t.Fatalf("analyzeLCPBreakdown: %v", err)
+	}
+	if result.IsError {
+		t.Fatal("a lab failure alone must not fail the tool")
+	}
+	response := decodeToolText[lcpBreakdownResponse](t, result)
+	if response.Field == nil || response.LabError == nil || response.Comparison != nil {
+		t.Errorf("response = %+v, want field data, a lab error, and no comparison", response)
+	}
+}
+
+func TestAnalyzeLCPBreakdown_RejectsBothStrategy(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := analyzeLCPBreakdown(
+		context.Background(),
+		breakdownAnalyzer{},
+		&breakdownCruxQuerier{},
+		lcpBreakdownInput{URL: "https://example.test/page", Strategy: "both"},
+	)
+	if err == nil {
+		t.Fatal("strategy both must be rejected")
+	}
+}
@@ -13,19 +13,17 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
-	"net"
 	"os"
 	"os/signal"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/ncosentino/google-psi-mcp/go/internal/apihttp"
 	"github.com/ncosentino/google-psi-mcp/go/internal/config"
 	"github.com/ncosentino/google-psi-mcp/go/internal/crux"
 	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
@@ -34,8 +32,12 @@ import (
 var version = "dev"
 
 const (
-	maxBatchURLs          = 10
-	maxConcurrentAnalyses = 4
+	maxBatchURLs             = 10
+	maxConcurrentAnalyses    = 4
+	maxBatchCruxTargets      = 10
+	maxConcurrentCruxQueries = 4
+	// minCruxQueryInterval keeps one process within CrUX's 150 queries per minute.
+	minCruxQueryInterval = 400 * time.Millisecond
 )
 
 type pageAnalyzer interface {
@@ -52,12 +54,15 @@ type analysisFailure struct {
 	Strategy  string `json:"strategy"`
 	Code      string `json:"code"`
 	Message   string `json:"message"`
+	Reason    string `json:"reason,omitempty"`
 	Retryable bool   `json:"retryable"`
+	Hint      string `json:"hint,omitempty"`
 }
 
 type analysisResponse struct {
-	Results []*pagespeed.AnalysisResult `json:"results"`
-	Errors  []analysisFailure           `json:"errors"`
+	Results      []*pagespeed.AnalysisResult `json:"results"`
+	Errors       []analysisFailure           `json:"errors"`
+	ThirdParties []batchThirdParties         `json:"thirdParties,omitempty"`
 }
 
 func main() {
@@ -141,18 +146,24 @@ func main() {
 // newServer builds the MCP server independently of its transport.
 func newServer(client pageAnalyzer, cruxClient cruxQuerier) *mcp.Server {
 	client = newLimitedPageAnalyzer(client, maxConcurrentAnalyses)
+	cruxClient = newLimitedCruxQuerier(cruxClient, maxConcurrentCruxQueries, minCruxQueryInterval)
 	srv := mcp.NewServer(&mcp.Implementation{
 		Name:    "google-psi-mcp",
 		Version: version,
-	}, nil)
+	}, &mcp.ServerOptions{
+		CompletionHandler: completeCruxMetric,
+	})
 	srv.AddReceivingMiddleware(coerceStringifiedArrayArgs(toolArrayFields))
 
 	mcp.AddTool(srv,
 		&mcp.Tool{
 			Name:        "analyze_page",
-			Description: "Analyze a single URL using Google PageSpeed Insights. Separates real-user CrUX field data from synthetic Lighthouse lab data and returns Lighthouse 13 insights with structured details. strategy defaults to both. categories defaults to performance, SEO, accessibility, and best-practices; agentic-browsing is experimental and must be requested explicitly.",
+			Description: "Analyze a single URL using Google PageSpeed Insights. Separates real-user CrUX field data from synthetic Lighthouse lab data and returns Lighthouse 13 insights with structured details. Each category's breakdown attributes its score to weighted audits with points lost. labData.prioritizedInsights ranks insights by estimated performance score gain from their metric savings, with a confidence level. metadata carries the Lighthouse config settings, host environment (including CPU benchmarkIndex), total run time, and runQualityWarnings that flag noisy runs such as a slow host CPU or an unusually long run. labFieldDiscrepancy quantifies lab-versus-field gaps (LCP, CLS, FCP, TTFB vs server response time, TBT vs INP) and explains them from the Lighthouse throttling settings and field distributions. strategy defaults to both. categories defaults to performance, SEO, accessibility, and best-practices; agentic-browsing is experimental and must be requested explicitly. labData.stackPacks lists detected frameworks such as WordPress, React, or Next.js, and matching audits carry stackAdvice with framework-specific remediation. labData.lcpElement identifies the LCP element (selector, snippet, bounding rect, resource URL) and labData.layoutShiftCulprits lists shifted elements with scores and causes. Set include_screenshots to receive the final screenshot and up to four filmstrip frames as image content instead of base64 inside audit details, and include_element_screenshots to receive crops of the LCP element and top layout shift culprits.",
 		},
 		func(ctx context.Context, _ *mcp.CallToolRequest, input analyzePageInput) (*mcp.CallToolResult, any, error) {
+			if input.IncludeScreenshots || input.IncludeElementScreenshots {
+				return analyzePageWithImages(ctx, client, input)
+			}
 			return analyzePages(ctx, client, []string{input.URL}, input.Strategy, input.Categories, input.Locale)
 		},
 	)
@@ -160,55 +171,147 @@ func newServer(client pageAnalyzer, cruxClient cruxQuerier) *mcp.Server {
 	mcp.AddTool(srv,
 		&mcp.Tool{
 			Name:        "get_crux_data",
-			Description: "Get current Chrome UX Report real-user data for a URL or origin. Supports all current CrUX metrics, including Core Web Vitals, LCP subparts, navigation types, RTT, resource types, and form-factor fractions. Requires the Chrome UX Report API to be enabled and allowed for the configured API key.",
+			Description: "Get current Chrome UX Report real-user data for a URL or origin. Supports all current CrUX metrics (see list_crux_metrics; aliases such as lcp, inp, and cls are accepted), including Core Web Vitals, LCP subparts, navigation types, RTT, resource types, and form-factor fractions. Upstream failures return a structured error with a code such as no_field_data, api_not_enabled, or rate_limited, and missing URL-level data suggests an origin fallback. When CrUX returns LCP image subparts, lcpBreakdown relates each subpart p75 to LCP and names the dominant bottleneck. Set fallback to true to retry a URL without data against its origin automatically; the result is then marked with originFallback and requestedTarget. percentiles (for example [50, 90]) adds estimatedPercentiles interpolated from each histogram, marked estimated: true because CrUX reports only p75 exactly. Requires the Chrome UX Report API to be enabled and allowed for the configured API key.",
 		},
 		func(ctx context.Context, _ *mcp.CallToolRequest, input cruxDataInput) (*mcp.CallToolResult, any, error) {
-			request, err := crux.NewQueryRequest(
-				input.Target,
-				input.TargetType,
-				input.FormFactor,
-				input.Metrics,
-				0,
-			)
-			if err != nil {
-				return nil, nil, err
-			}
-			result, err := cruxClient.QueryCurrent(ctx, request)
-			if err != nil {
-				return nil, nil, err
-			}
-			return jsonToolResult(result)
+			return queryCruxCurrent(ctx, cruxClient, input)
 		},
 	)
 
 	mcp.AddTool(srv,
 		&mcp.Tool{
 			Name:        "get_crux_history",
-			Description: "Get up to 40 weekly Chrome UX Report collection periods for a URL or origin. Returns real-user metric timeseries with null values for unavailable periods. Requires the Chrome UX Report API to be enabled and allowed for the configured API key.",
+			Description: "Get up to 40 weekly Chrome UX Report collection periods for a URL or origin. Returns real-user metric timeseries with null values for unavailable periods. Upstream failures return the same structured error as get_crux_data, and fallback retries a URL without data against its origin. percentiles adds per-period estimates interpolated from the histogram timeseries. Requires the Chrome UX Report API to be enabled and allowed for the configured API key.",
 		},
 		func(ctx context.Context, _ *mcp.CallToolRequest, input cruxHistoryInput) (*mcp.CallToolResult, any, error) {
-			request, err := crux.NewQueryRequest(
-				input.Target,
-				input.TargetType,
-				input.FormFactor,
-				input.Metrics,
-				input.CollectionPeriodCount,
-			)
-			if err != nil {
-				return nil, nil, err
-			}
-			result, err := cruxClient.QueryHistory(ctx, request)
-			if err != nil {
-				return nil, nil, err
-			}
-			return jsonToolResult(result)
+			return queryCruxHistory(ctx, cruxClient, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "list_crux_metrics",
+			Description: "List the Chrome UX Report metrics accepted by the CrUX tools, with units, descriptions, the aggregations each returns (histogram, percentiles, fractions), whether it is a Core Web Vital, rating thresholds, and accepted aliases such as lcp for largest_contentful_paint. Unknown metric names are rejected before calling the API.",
+		},
+		func(context.Context, *mcp.CallToolRequest, listCruxMetricsInput) (*mcp.CallToolResult, any, error) {
+			return listCruxMetrics()
+		},
+	)
+
+	srv.AddResourceTemplate(
+		&mcp.ResourceTemplate{
+			Name:        "crux_metric",
+			URITemplate: cruxMetricURITemplate,
+			Description: "One Chrome UX Report metric catalog entry. The metric argument supports completion of metric names and aliases.",
+			MIMEType:    "application/json",
+		},
+		readCruxMetric,
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "analyze_crux_trend",
+			Description: "Analyze Chrome UX Report history for a URL or origin and return per-metric statistics instead of raw timeseries: least-squares slope per collection period, improving or regressing direction, percentage change over configurable windows of periods (default 4 and 12), the collection period where a step change happened, and current and longest passing or failing streaks against Google's thresholds. Unavailable periods are skipped, not treated as zero. collection_period_count defaults to 25.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input cruxTrendInput) (*mcp.CallToolResult, any, error) {
+			return analyzeCruxTrend(ctx, cruxClient, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "get_crux_data_batch",
+			Description: "Get current Chrome UX Report real-user data for up to 10 URLs or origins across one or more form factors in a single call. Every target and form_factors combination is queried with bounded concurrency and rate limiting. Returns successful results plus structured per-query errors. form_factors defaults to all. percentiles behaves as in get_crux_data.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input cruxBatchInput) (*mcp.CallToolResult, any, error) {
+			return queryCruxBatch(ctx, cruxClient, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "benchmark_origins",
+			Description: "Benchmark an origin against up to 9 competitor origins using Chrome UX Report real-user data. Returns a ranked table per Core Web Vital (LCP, INP, CLS) with p75, the good proportion from the histogram, pass or fail against Google's good threshold, and the p75 trend over the last trend_periods collection periods, plus each origin's overall Core Web Vitals verdict. trend_periods defaults to 6.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input benchmarkOriginsInput) (*mcp.CallToolResult, any, error) {
+			return benchmarkOrigins(ctx, cruxClient, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "analyze_lcp_breakdown",
+			Description: "Break down Largest Contentful Paint for one URL into time to first byte, resource load delay, resource load duration, and element render delay. Queries Chrome UX Report LCP image subparts (p75 and share of LCP p75) and the Lighthouse lcp-breakdown-insight for the same device class, identifies the dominant bottleneck in each, and reports whether they agree with remediation advice. strategy is mobile (default, CrUX phone) or desktop. fallback retries CrUX against the origin when the URL has no data.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input lcpBreakdownInput) (*mcp.CallToolResult, any, error) {
+			return analyzeLCPBreakdown(ctx, client, cruxClient, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "simulate_score",
+			Description: "Project the Lighthouse performance score for hypothetical lab metric changes, for example cutting LCP by 800 ms. Uses Lighthouse's log-normal scoring curves and metric weights (FCP 10, Speed Index 10, LCP 25, TBT 30, CLS 25) for mobile or desktop. Provide url to measure a fresh baseline with PSI, or metrics with raw values for fcp, speedIndex, lcp, tbt, and cls (milliseconds; cls unitless). changes adds deltas and targets sets absolute values. Returns baseline and projected scores with per-metric scores and contributions in points. strategy defaults to mobile.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input simulateScoreInput) (*mcp.CallToolResult, any, error) {
+			return simulateScore(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "get_network_waterfall",
+			Description: "Get the network request waterfall from a Lighthouse run of one URL. Returns every request from the network-requests audit in start order with URL, resource type, MIME type, protocol, priority, status code, start and end times and duration (ms after navigation start), transfer and resource size (bytes), and owning entity, plus totals, breakdowns by resource type and by entity with first-party flags, and per-origin RTT and server latency. Set text_waterfall to also receive a fixed-width text waterfall chart. strategy is mobile (default) or desktop.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input networkWaterfallInput) (*mcp.CallToolResult, any, error) {
+			return getNetworkWaterfall(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "analyze_script_treemap",
+			Description: "Analyze the JavaScript bundles of one URL from the Lighthouse script treemap. Returns totals, the largest scripts with transfer size, unused bytes and unused ratio, the source-mapped modules with the most unused bytes and the largest modules, modules duplicated across bundles with wasted bytes, and script totals grouped by first-party, third-party, and unattributed and by Lighthouse entity. limit caps the scripts and modules returned (default 10, max 50); totals and groups always cover every script. strategy is mobile (default) or desktop.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input scriptTreemapInput) (*mcp.CallToolResult, any, error) {
+			return analyzeScriptTreemap(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "third_party_report",
+			Description: "Report what each Lighthouse entity costs on one URL. Joins labData.entities with the network requests, third-party summary, JavaScript bootup time, and long tasks to return per-entity request count, transfer size (bytes), main-thread time, scripting time, and blocking time (ms), with the entity category (such as analytics, ad, or tag-manager) and first-party flag, plus first-party and third-party totals and transfer shares. Entities are ordered by main-thread time. strategy is mobile (default) or desktop.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input thirdPartyReportInput) (*mcp.CallToolResult, any, error) {
+			return thirdPartyReport(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "get_request_chains",
+			Description: "Get the critical request chain tree of one URL from the Lighthouse network dependency tree (or critical-request-chains before Lighthouse 13). Returns the typed tree with each request's URL, end time (ms after navigation start), transfer size, depth, and whether it is on the longest chain, plus the request count, maximum depth, and the longest chain's duration, depth, transfer size, and URLs. A second content item renders the tree as indented text with the longest chain marked; set mermaid to also receive a Mermaid flowchart. strategy is mobile (default) or desktop.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input requestChainsInput) (*mcp.CallToolResult, any, error) {
+			return getRequestChains(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "get_accessibility_report",
+			Description: "Run the Lighthouse accessibility category for one URL and group failing audits by WCAG success criterion and level (A, AA, AAA), derived from the axe rule behind each audit. Every failing audit lists its failing elements with selector, HTML snippet, label, and fix explanation; failing audits with no WCAG criterion are listed as best practices, and audits Lighthouse cannot automate are returned as a manual-check checklist. Pass the report from an earlier call as baseline to also receive a diff of fixed, regressed, and changed audits and the score change. strategy is mobile (default) or desktop.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input accessibilityReportInput) (*mcp.CallToolResult, any, error) {
+			return getAccessibilityReport(ctx, client, input)
 		},
 	)
 
 	mcp.AddTool(srv,
 		&mcp.Tool{
 			Name:        "analyze_pages",
-			Description: "Analyze multiple URLs using Google PageSpeed Insights. Returns separate real-user field data and Lighthouse lab data for every URL and strategy. strategy defaults to both. categories defaults to performance, SEO, accessibility, and best-practices; agentic-browsing is experimental and must be requested explicitly.",
+			Description: "Analyze multiple URLs using Google PageSpeed Insights. Returns separate real-user field data and Lighthouse lab data for every URL and strategy, plus thirdParties: per-strategy third-party costs aggregated across the URLs, with the number of pages each entity appeared on. strategy defaults to both. categories defaults to performance, SEO, accessibility, and best-practices; agentic-browsing is experimental and must be requested explicitly.",
 		},
 		func(ctx context.Context, _ *mcp.CallToolRequest, input analyzePagesInput) (*mcp.CallToolResult, any, error) {
 			return analyzePages(ctx, client, input.URLs, input.Strategy, input.Categories, input.Locale)
@@ -220,10 +323,12 @@ func newServer(client pageAnalyzer, cruxClient cruxQuerier) *mcp.Server {
 
 // analyzePageInput is the input schema for the analyze_page tool.
 type analyzePageInput struct {
-	URL        string   `json:"url"`
-	Strategy   string   `json:"strategy,omitempty"`
-	Categories []string `json:"categories,omitempty"`
-	Locale     string   `json:"locale,omitempty"`
+	URL                       string   `json:"url"`
+	Strategy                  string   `json:"strategy,omitempty"`
+	Categories                []string `json:"categories,omitempty"`
+	Locale                    string   `json:"locale,omitempty"`
+	IncludeScreenshots        bool     `json:"include_screenshots,omitempty"`
+	IncludeElementScreenshots bool     `json:"include_element_screenshots,omitempty"`
 }
 
 // analyzePagesInput is the input schema for the analyze_pages tool.
@@ -236,22 +341,28 @@ type analyzePagesInput struct {
 
 // cruxDataInput is the input schema for current Chrome UX Report data.
 type cruxDataInput struct {
-	Target     string   `json:"target"`
-	TargetType string   `json:"target_type,omitempty"`
-	FormFactor string   `json:"form_factor,omitempty"`
-	Metrics    []string `json:"metrics,omitempty"`
+	Target      string    `json:"target"`
+	TargetType  string    `json:"target_type,omitempty"`
+	FormFactor  string    `json:"form_factor,omitempty"`
+	Metrics     []string  `json:"metrics,omitempty"`
+	Fallback    bool      `json:"fallback,omitempty"`
+	Percentiles []float64 `json:"percentiles,omitempty"`
 }
 
 // cruxHistoryInput is the input schema for historical Chrome UX Report data.
 type cruxHistoryInput struct {
-	Target                string   `json:"target"`
-	TargetType            string   `json:"target_type,omitempty"`
-	FormFactor            string   `json:"form_factor,omitempty"`
-	Metrics               []string `json:"metrics,omitempty"`
-	CollectionPeriodCount int      `json:"collection_period_count,omitempty"`
+	Target                string    `json:"target"`
+	TargetType            string    `json:"target_type,omitempty"`
+	FormFactor            string    `json:"form_factor,omitempty"`
+	Metrics               []string  `json:"metrics,omitempty"`
+	CollectionPeriodCount int       `json:"collection_period_count,omitempty"`
+	Fallback              bool      `json:"fallback,omitempty"`
+	Percentiles           []float64 `json:"percentiles,omitempty"`
 }
 
-// analyzePages runs PSI analysis for the given URLs and strategy, returning a JSON tool result.
+// analyzePages runs PSI analysis for the given URLs and strategy, returning a
+// JSON tool result. Batches of several URLs also aggregate third-party costs
+// per strategy.
 func analyzePages(
 	ctx context.Context,
 	client pageAnalyzer,
@@ -260,16 +371,35 @@ func analyzePages(
 	categories []string,
 	locale string,
 ) (*mcp.CallToolResult, any, error) {
+	response, err := runAnalyses(ctx, client, urls, strategy, categories, locale)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(urls) > 1 {
+		response.ThirdParties = aggregateThirdParties(response.Results)
+	}
+	return jsonToolResult(response)
+}
+
+// runAnalyses runs PSI analysis for every URL and strategy concurrently.
+func runAnalyses(
+	ctx context.Context,
+	client pageAnalyzer,
+	urls []string,
+	strategy string,
+	categories []string,
+	locale string,
+) (analysisResponse, error) {
 	if len(urls) == 0 {
-		return nil, nil, fmt.Errorf("at least one URL is required")
+		return analysisResponse{}, fmt.Errorf("at least one URL is required")
 	}
 	if len(urls) > maxBatchURLs {
-		return nil, nil, fmt.Errorf("at most %d URLs may be analyzed per call", maxBatchURLs)
+		return analysisResponse{}, fmt.Errorf("at most %d URLs may be analyzed per call", maxBatchURLs)
 	}
 
 	strategies, err := pagespeed.ResolveStrategies(strategy)
 	if err != nil {
-		return nil, nil, err
+		return analysisResponse{}, err
 	}
 
 	requests := make([]pagespeed.AnalysisRequest, 0, len(urls)*len(strategies))
@@ -282,7 +412,7 @@ func analyzePages(
 				locale,
 			)
 			if err != nil {
-				return nil, nil, err
+				return analysisResponse{}, err
 			}
 			requests = append(requests, request)
 		}
@@ -320,7 +450,7 @@ func analyzePages(
 	waitGroup.Wait()
 
 	if ctx.Err() != nil {
-		return nil, nil, ctx.Err()
+		return analysisResponse{}, ctx.Err()
 	}
 
 	response := analysisResponse{
@@ -335,42 +465,95 @@ func analyzePages(
 			response.Errors = append(response.Errors, *entry.failure)
 		}
 	}
+	return response, nil
+}
 
-	return jsonToolResult(response)
+// analyzeLab runs a performance analysis of one URL for a single strategy,
+// which defaults to mobile, on behalf of the named tool. A PSI failure is
+// returned as a structured tool error result instead of an analysis result.
+func analyzeLab(
+	ctx context.Context,
+	client pageAnalyzer,
+	inputURL string,
+	strategy string,
+	tool string,
+) (*pagespeed.AnalysisResult, *mcp.CallToolResult, error) {
+	return analyzeLabCategories(ctx, client, inputURL, strategy, []string{"performance"}, tool)
+}
+
+// analyzeLabCategories is analyzeLab for the given Lighthouse categories.
+func analyzeLabCategories(
+	ctx context.Context,
+	client pageAnalyzer,
+	inputURL string,
+	strategy string,
+	categories []string,
+	tool string,
+) (*pagespeed.AnalysisResult, *mcp.CallToolResult, error) {
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	if strategy == "" {
+		strategy = "mobile"
+	}
+	if _, ok := strategyFormFactors[strategy]; !ok {
+		return nil, nil, fmt.Errorf("strategy must be mobile or desktop")
+	}
+	request, err := pagespeed.NewAnalysisRequest(inputURL, strategy, categories, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := client.Analyze(ctx, request)
+	if err != nil {
+		slog.Warn(
+			"PSI analysis failed",
+			"tool",
+			tool,
+			"url",
+			request.URL,
+			"strategy",
+			request.Strategy,
+			"err",
+			err,
+		)
+		failure, _, err := errorToolResult(classifyAnalysisFailure(request, err))
+		return nil, failure, err
+	}
+	if result.LabData == nil {
+		return nil, nil, fmt.Errorf("PSI returned no Lighthouse lab data for %s", request.URL)
+	}
+	return result, nil, nil
+}
+
+// auditUnavailableResult reports a lab result that lacks the audits a tool
+// reads as a structured tool error.
+func auditUnavailableResult(
+	result *pagespeed.AnalysisResult,
+	err error,
+) (*mcp.CallToolResult, any, error) {
+	return errorToolResult(analysisFailure{
+		InputURL:  result.Metadata.InputURL,
+		Strategy:  result.Metadata.Strategy,
+		Code:      "audit_unavailable",
+		Message:   err.Error(),
+		Retryable: false,
+		Hint: "The Lighthouse run did not report the audit this tool reads. " +
+			"Check runWarnings from analyze_page, or retry in case the page failed to load fully.",
+	})
 }
 
 func classifyAnalysisFailure(
 	request pagespeed.AnalysisRequest,
 	err error,
 ) analysisFailure {
-	failure := analysisFailure{
-		InputURL: request.URL,
-		Strategy: request.Strategy,
-		Code:     "request_failed",
-		Message:  err.Error(),
-	}
-
-	var statusError *apihttp.StatusError
-	if errors.As(err, &statusError) {
-		failure.Retryable = statusError.Retryable()
-		switch {
-		case statusError.StatusCode == 429:
-			failure.Code = "rate_limited"
-		case statusError.StatusCode >= 500:
-			failure.Code = "upstream_unavailable"
-		default:
-			failure.Code = "upstream_rejected"
-		}
-		return failure
-	}
-
-	var networkError net.Error
-	if errors.Is(err, context.DeadlineExceeded) ||
-		(errors.As(err, &networkError) && networkError.Timeout()) {
-		failure.Code = "timeout"
-		failure.Retryable = true
+	failure := describeAPIFailure(err)
+	return analysisFailure{
+		InputURL:  request.URL,
+		Strategy:  request.Strategy,
+		Code:      failure.Code,
+		Message:   failure.Message,
+		Reason:    failure.Reason,
+		Retryable: failure.Retryable,
+		Hint:      failure.Hint,
 	}
-	return failure
 }
 
 func jsonToolResult(value any) (*mcp.CallToolResult, any, error) {
@@ -386,6 +569,15 @@ func jsonToolResult(value any) (*mcp.CallToolResult, any, error) {
 	}, nil, nil
 }
 
+func errorToolResult(value any) (*mcp.CallToolResult, any, error) {
+	result, _, err := jsonToolResult(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.IsError = true
+	return result, nil, nil
+}
+
 func splitAndTrim(value string) []string {
 	parts := strings.Split(value, ",")
 	result := make([]string, 0, len(parts))
@@ -100,6 +100,17 @@ func TestNewServer_RegistersTools(t *testing.T) {
 		"analyze_pages",
 		"get_crux_data",
 		"get_crux_history",
+		"get_crux_data_batch",
+		"benchmark_origins",
+		"analyze_crux_trend",
+		"list_crux_metrics",
+		"analyze_lcp_breakdown",
+		"simulate_score",
+		"get_network_waterfall",
+		"analyze_script_treemap",
+		"third_party_report",
+		"get_request_chains",
+		"get_accessibility_report",
 	} {
 		found := false
 		for _, tool := range result.Tools {
@@ -276,3 +287,84 @@ func TestClassifyAnalysisFailure_UsesStructuredRetryability(t *testing.T) {
 		t.Errorf("bad-request failure = %+v", badRequest)
 	}
 }
+
+func TestClassifyAnalysisFailure_DecodesGoogleErrorReasons(t *testing.T) {
+	t.Parallel()
+
+	request, err := pagespeed.NewAnalysisRequest(
+		"https://example.test",
+		"mobile",
+		nil,
+		"",
+	)
+	if err != nil {
+		t.Fatalf("NewAnalysisRequest: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		err       *apihttp.StatusError
+		wantCode  string
+		retryable bool
+	}{
+		{
+			name: "service disabled",
+			err: &apihttp.StatusError{
+				Service:    "PSI API",
+				StatusCode: http.StatusForbidden,
+				Status:     "PERMISSION_DENIED",
+				Message:    "PageSpeed Insights API has not been used in project 123.",
+				Reason:     "SERVICE_DISABLED",
+				Metadata:   map[string]string{"service": "pagespeedonline.googleapis.com"},
+			},
+			wantCode: "api_not_enabled",
+		},
+		{
+			name: "invalid key",
+			err: &apihttp.StatusError{
+				Service:    "PSI API",
+				StatusCode: http.StatusBadRequest,
+				Status:     "INVALID_ARGUMENT",
+				Message:    "API key not valid. Please pass a valid API key.",
+				Reason:     "API_KEY_INVALID",
+			},
+			wantCode: "api_key_invalid",
+		},
+		{
+			name: "quota exhausted",
+			err: &apihttp.StatusError{
+				Service:    "PSI API",
+				StatusCode: http.StatusTooManyRequests,
+				Status:     "RESOURCE_EXHAUSTED",
+				Message:    "Quota exceeded.",
+				Reason:     "RATE_LIMIT_EXCEEDED",
+			},
+			wantCode:  "rate_limited",
+			retryable: true,
+		},
+		{
+			name: "unreachable document",
+			err: &apihttp.StatusError{
+				Service:    "PSI API",
+				StatusCode: http.StatusInternalServerError,
+				Message:    "Lighthouse returned error: FAILED_DOCUMENT_REQUEST. Lighthouse was unable to reliably load the page.",
+				Reason:     "lighthouseUserError",
+			},
+			wantCode: "url_unreachable",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			failure := classifyAnalysisFailure(request, test.err)
+			if failure.Code != test.wantCode || failure.Retryable != test.retryable {
+				t.Errorf("failure = %+v, want code %s retryable %v", failure, test.wantCode, test.retryable)
+			}
+			if failure.Hint == "" {
+				t.Error("failure hint must be populated")
+			}
+		})
+	}
+}
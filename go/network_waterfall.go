@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+// networkWaterfallInput is the input schema for the get_network_waterfall tool.
+type networkWaterfallInput struct {
+	URL           string `json:"url"`
+	Strategy      string `json:"strategy,omitempty"`
+	TextWaterfall bool   `json:"text_waterfall,omitempty"`
+}
+
+type networkWaterfallResponse struct {
+	InputURL  string                      `json:"inputUrl"`
+	FinalURL  string                      `json:"finalUrl,omitempty"`
+	Strategy  string                      `json:"strategy"`
+	Waterfall *pagespeed.NetworkWaterfall `json:"waterfall"`
+}
+
+// getNetworkWaterfall runs Lighthouse for one URL and returns its network
+// requests with summaries, optionally followed by a rendered text waterfall.
+func getNetworkWaterfall(
+	ctx context.Context,
+	client pageAnalyzer,
+	input networkWaterfallInput,
+) (*mcp.CallToolResult, any, error) {
+	result, failure, err := analyzeLab(ctx, client, input.URL, input.Strategy, "get_network_waterfall")
+	if err != nil || failure != nil {
+		return failure, nil, err
+	}
+	waterfall, err := pagespeed.ParseNetworkWaterfall(result.LabData)
+	if err != nil {
+		return auditUnavailableResult(result, err)
+	}
+
+	toolResult, _, err := jsonToolResult(networkWaterfallResponse{
+		InputURL:  result.Metadata.InputURL,
+		FinalURL:  result.Metadata.FinalURL,
+		Strategy:  result.Metadata.Strategy,
+		Waterfall: waterfall,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if input.TextWaterfall {
+		toolResult.Content = append(toolResult.Content, &mcp.TextContent{Text: waterfall.Text()})
+	}
+	return toolResult, nil, nil
+}
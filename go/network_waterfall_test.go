@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+// fixtureAnalyzer returns the shared PSI fixture parsed as the response to
+// each request.
+type fixtureAnalyzer struct {
+	t *testing.T
+}
+
+func (a fixtureAnalyzer) Analyze(
+	_ context.Context,
+	request pagespeed.AnalysisRequest,
+) (*pagespeed.AnalysisResult, error) {
+	a.t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "testdata", "psi-lighthouse-13.4.json"))
+	if err != nil {
+		a.t.Fatalf("read PSI fixture: %v", err)
+	}
+	return pagespeed.ParseResponse(request, data)
+}
+
+// emptyLabAnalyzer returns lab data without any audits.
+type emptyLabAnalyzer struct{}
+
+func (emptyLabAnalyzer) Analyze(
+	_ context.Context,
+	request pagespeed.AnalysisRequest,
+) (*pagespeed.AnalysisResult, error) {
+	return &pagespeed.AnalysisResult{
+		Metadata: pagespeed.AnalysisMetadata{InputURL: request.URL, Strategy: request.Strategy},
+		LabData:  &pagespeed.LabData{},
+	}, nil
+}
+
+// assertAuditUnavailable checks that result is an audit_unavailable tool error.
+func assertAuditUnavailable(t *testing.T, result *mcp.CallToolResult, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("returned protocol error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("result must be marked as a tool error")
+	}
+	failure := decodeToolText[analysisFailure](t, result)
+	if failure.Code != "audit_unavailable" || failure.Retryable || failure.Strategy != "mobile" ||
+		failure.Message == "" || failure.Hint == "" {
+		t.Errorf("failure = %+v, want audit_unavailable", failure)
+	}
+}
+
+func TestGetNetworkWaterfall_ReturnsRequestsAndText(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := getNetworkWaterfall(context.Background(), fixtureAnalyzer{t: t}, networkWaterfallInput{
+		URL:           "https://example.test/page",
+		TextWaterfall: true,
+	})
+	if err != nil {
+		t.Fatalf("getNetworkWaterfall: %v", err)
+	}
+	response := decodeToolText[networkWaterfallResponse](t, result)
+	if response.Strategy != "mobile" || len(response.Waterfall.Requests) != 6 ||
+		response.Waterfall.Summary.ByEntity[0].Name != "Example" {
+		t.Errorf("response = %+v", response)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("content = %d items, want JSON and text waterfall", len(result.Content))
+	}
+	if text := result.Content[1].(*mcp.TextContent).Text; !strings.Contains(text, "https://example.test/hero.jpg") {
+		t.Errorf("text waterfall = %q", text)
+	}
+}
+
+func TestGetNetworkWaterfall_MissingAudit_ReturnsStructuredToolError(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := getNetworkWaterfall(context.Background(), emptyLabAnalyzer{}, networkWaterfallInput{
+		URL: "https://example.test/page",
+	})
+	assertAuditUnavailable(t, result, err)
+}
+
+func TestGetNetworkWaterfall_RejectsBothStrategies(t *testing.T) {
+	t.Parallel()
+
+	analyzer := &trackingAnalyzer{}
+	if _, _, err := getNetworkWaterfall(context.Background(), analyzer, networkWaterfallInput{
+		URL:      "https://example.test/page",
+		Strategy: "both",
+	}); err == nil {
+		t.Fatal("strategy both must be rejected")
+	}
+	if calls := analyzer.calls.Load(); calls != 0 {
+		t.Errorf("API calls = %d, want 0", calls)
+	}
+}
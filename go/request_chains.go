@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+// requestChainsInput is the input schema for the get_request_chains tool.
+type requestChainsInput struct {
+	URL      string `json:"url"`
+	Strategy string `json:"strategy,omitempty"`
+	Mermaid  bool   `json:"mermaid,omitempty"`
+}
+
+type requestChainsResponse struct {
+	InputURL string                   `json:"inputUrl"`
+	FinalURL string                   `json:"finalUrl,omitempty"`
+	Strategy string                   `json:"strategy"`
+	Chains   *pagespeed.RequestChains `json:"chains"`
+}
+
+// getRequestChains runs Lighthouse for one URL and returns its critical
+// request chain tree as JSON followed by an indented text tree and,
+// optionally, a Mermaid flowchart.
+func getRequestChains(
+	ctx context.Context,
+	client pageAnalyzer,
+	input requestChainsInput,
+) (*mcp.CallToolResult, any, error) {
+	result, failure, err := analyzeLab(ctx, client, input.URL, input.Strategy, "get_request_chains")
+	if err != nil || failure != nil {
+		return failure, nil, err
+	}
+	chains, err := pagespeed.ParseRequestChains(result.LabData)
+	if err != nil {
+		return auditUnavailableResult(result, err)
+	}
+
+	toolResult, _, err := jsonToolResult(requestChainsResponse{
+		InputURL: result.Metadata.InputURL,
+		FinalURL: result.Metadata.FinalURL,
+		Strategy: result.Metadata.Strategy,
+		Chains:   chains,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	toolResult.Content = append(toolResult.Content, &mcp.TextContent{Text: chains.Text()})
+	if input.Mermaid {
+		toolResult.Content = append(toolResult.Content, &mcp.TextContent{
+			Text: "```mermaid\n" + chains.Mermaid() + "```\n",
+		})
+	}
+	return toolResult, nil, nil
+}
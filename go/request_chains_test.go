@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestGetRequestChains_ReturnsTreeTextAndMermaid(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := getRequestChains(context.Background(), fixtureAnalyzer{t: t}, requestChainsInput{
+		URL:     "https://example.test/page",
+		Mermaid: true,
+	})
+	if err != nil {
+		t.Fatalf("getRequestChains: %v", err)
+	}
+	response := decodeToolText[requestChainsResponse](t, result)
+	if response.Chains.LongestChain.Duration != 1900 || response.Chains.LongestChain.Depth != 3 {
+		t.Errorf("longest chain = %+v", response.Chains.LongestChain)
+	}
+	if len(result.Content) != 3 {
+		t.Fatalf("content = %d items, want JSON, text tree, and Mermaid", len(result.Content))
+	}
+	if text := result.Content[1].(*mcp.TextContent).Text; !strings.HasPrefix(text, "Longest chain: 1900 ms") {
+		t.Errorf("text tree = %q", text)
+	}
+	if mermaid := result.Content[2].(*mcp.TextContent).Text; !strings.HasPrefix(mermaid, "```mermaid\nflowchart LR\n") {
+		t.Errorf("mermaid = %q", mermaid)
+	}
+}
+
+func TestGetRequestChains_OmitsMermaidByDefault(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := getRequestChains(context.Background(), fixtureAnalyzer{t: t}, requestChainsInput{
+		URL: "https://example.test/page",
+	})
+	if err != nil {
+		t.Fatalf("getRequestChains: %v", err)
+	}
+	if len(result.Content) != 2 {
+		t.Errorf("content = %d items, want JSON and text tree", len(result.Content))
+	}
+}
+
+func TestGetRequestChains_MissingAudit_ReturnsStructuredToolError(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := getRequestChains(context.Background(), emptyLabAnalyzer{}, requestChainsInput{
+		URL: "https://example.test/page",
+	})
+	assertAuditUnavailable(t, result, err)
+}
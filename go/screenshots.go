@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+// maxLayoutShiftCrops limits the layout shift culprits cropped per result.
+const maxLayoutShiftCrops = 3
+
+// analyzePageWithImages runs analyze_page and returns the requested
+// Lighthouse screenshots and element crops as image content after the JSON
+// result. Each image follows a short text label; screenshot base64 data is
+// removed from the audit details.
+func analyzePageWithImages(
+	ctx context.Context,
+	client pageAnalyzer,
+	input analyzePageInput,
+) (*mcp.CallToolResult, any, error) {
+	response, err := runAnalyses(
+		ctx,
+		client,
+		[]string{input.URL},
+		input.Strategy,
+		input.Categories,
+		input.Locale,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var images []mcp.Content
+	for _, result := range response.Results {
+		if result.LabData == nil {
+			continue
+		}
+		if input.IncludeScreenshots {
+			for _, screenshot := range result.LabData.Screenshots.Images() {
+				images = append(images,
+					&mcp.TextContent{Text: fmt.Sprintf(
+						"%s %s screenshot at %.0f ms: %s",
+						result.Metadata.Strategy,
+						screenshot.Kind,
+						screenshot.Timing,
+						result.Metadata.FinalURL,
+					)},
+					&mcp.ImageContent{Data: screenshot.Data, MIMEType: screenshot.MIMEType},
+				)
+			}
+			result.LabData.StripScreenshotData()
+		}
+		if input.IncludeElementScreenshots {
+			images = append(images, elementImages(result)...)
+		}
+	}
+
+	toolResult, _, err := jsonToolResult(response)
+	if err != nil {
+		return nil, nil, err
+	}
+	toolResult.Content = append(toolResult.Content, images...)
+	return toolResult, nil, nil
+}
+
+// elementImages crops the LCP element and the top layout shift culprits out
+// of the result's screenshots. Elements that cannot be cropped get a text
+// note instead of an image.
+func elementImages(result *pagespeed.AnalysisResult) []mcp.Content {
+	type element struct {
+		label string
+		node  pagespeed.NodeValue
+	}
+	var elements []element
+	if lcp := result.LabData.LCPElement; lcp != nil {
+		elements = append(elements, element{label: "LCP element", node: lcp.Element})
+	}
+	for index, culprit := range result.LabData.LayoutShiftCulprits {
+		if index == maxLayoutShiftCrops {
+			break
+		}
+		elements = append(elements, element{label: "layout shift culprit", node: culprit.Element})
+	}
+
+	contents := make([]mcp.Content, 0, len(elements)*2)
+	for _, element := range elements {
+		crop, err := result.LabData.Screenshots.CropElement(element.node)
+		if err != nil {
+			contents = append(contents, &mcp.TextContent{Text: fmt.Sprintf(
+				"%s %s %s could not be cropped: %v",
+				result.Metadata.Strategy,
+				element.label,
+				element.node.Selector,
+				err,
+			)})
+			continue
+		}
+		contents = append(contents,
+			&mcp.TextContent{Text: fmt.Sprintf(
+				"%s %s %s: %s",
+				result.Metadata.Strategy,
+				element.label,
+				element.node.Selector,
+				element.node.NodeLabel,
+			)},
+			&mcp.ImageContent{Data: crop.Data, MIMEType: crop.MIMEType},
+		)
+	}
+	return contents
+}
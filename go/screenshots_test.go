@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+type screenshotAnalyzer struct{}
+
+func (screenshotAnalyzer) Analyze(
+	_ context.Context,
+	request pagespeed.AnalysisRequest,
+) (*pagespeed.AnalysisResult, error) {
+	return &pagespeed.AnalysisResult{
+		Metadata: pagespeed.AnalysisMetadata{
+			InputURL: request.URL,
+			FinalURL: request.URL,
+			Strategy: request.Strategy,
+		},
+		LabData: &pagespeed.LabData{
+			UnscoredAudits: []pagespeed.LighthouseAudit{{
+				ID:      "final-screenshot",
+				Details: json.RawMessage(`{"type":"screenshot","timing":3100,"data":"data:image/jpeg;base64,/9j/"}`),
+			}},
+			Screenshots: &pagespeed.LabScreenshots{
+				Final: &pagespeed.Screenshot{
+					Kind: pagespeed.ScreenshotFinal, Timing: 3100, MIMEType: "image/jpeg", Data: []byte{0xff, 0xd8},
+				},
+				Filmstrip: []pagespeed.Screenshot{{
+					Kind: pagespeed.ScreenshotFilmstrip, Timing: 300, MIMEType: "image/jpeg", Data: []byte{0xff, 0xd9},
+				}},
+				FilmstripFrameCount: 1,
+			},
+		},
+	}, nil
+}
+
+func TestAnalyzePageWithScreenshots_ReturnsImageContent(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := analyzePageWithImages(context.Background(), screenshotAnalyzer{}, analyzePageInput{
+		URL:                "https://example.test/page",
+		Strategy:           "mobile",
+		IncludeScreenshots: true,
+	})
+	if err != nil {
+		t.Fatalf("analyzePageWithImages: %v", err)
+	}
+	if len(result.Content) != 5 {
+		t.Fatalf("content = %d items, want JSON plus two labeled images", len(result.Content))
+	}
+	label, ok := result.Content[1].(*mcp.TextContent)
+	if !ok || !strings.Contains(label.Text, "mobile final screenshot at 3100 ms") {
+		t.Errorf("label = %+v, want the final screenshot label", result.Content[1])
+	}
+	image, ok := result.Content[2].(*mcp.ImageContent)
+	if !ok || image.MIMEType != "image/jpeg" || len(image.Data) != 2 {
+		t.Errorf("image = %+v, want the decoded final screenshot", result.Content[2])
+	}
+	if _, ok := result.Content[4].(*mcp.ImageContent); !ok {
+		t.Errorf("last content = %T, want the filmstrip frame", result.Content[4])
+	}
+
+	response := decodeToolText[analysisResponse](t, result)
+	details := string(response.Results[0].LabData.UnscoredAudits[0].Details)
+	if strings.Contains(details, "base64") || !strings.Contains(details, "3100") {
+		t.Errorf("details = %s, want timing without image data", details)
+	}
+}
+
+type elementAnalyzer struct{}
+
+func (elementAnalyzer) Analyze(
+	_ context.Context,
+	request pagespeed.AnalysisRequest,
+) (*pagespeed.AnalysisResult, error) {
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 40, 60)), nil); err != nil {
+		return nil, err
+	}
+	return &pagespeed.AnalysisResult{
+		Metadata: pagespeed.AnalysisMetadata{InputURL: request.URL, Strategy: request.Strategy},
+		LabData: &pagespeed.LabData{
+			LCPElement: &pagespeed.LCPElement{Element: pagespeed.NodeValue{
+				LHID: "page-0-IMG", Selector: "main > img.hero", NodeLabel: "Hero image",
+			}},
+			LayoutShiftCulprits: []pagespeed.LayoutShiftCulprit{{Element: pagespeed.NodeValue{
+				Selector: "body > div.ad-slot",
+			}}},
+			Screenshots: &pagespeed.LabScreenshots{FullPage: &pagespeed.FullPageScreenshot{
+				Width: 40, Height: 60, MIMEType: "image/jpeg", Data: encoded.Bytes(),
+				Nodes: map[string]pagespeed.Rect{
+					"page-0-IMG": {Top: 10, Bottom: 30, Left: 5, Right: 35, Width: 30, Height: 20},
+				},
+			}},
+		},
+	}, nil
+}
+
+func TestAnalyzePageWithImages_CropsElements(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := analyzePageWithImages(context.Background(), elementAnalyzer{}, analyzePageInput{
+		URL:                       "https://example.test/page",
+		Strategy:                  "mobile",
+		IncludeElementScreenshots: true,
+	})
+	if err != nil {
+		t.Fatalf("analyzePageWithImages: %v", err)
+	}
+	if len(result.Content) != 4 {
+		t.Fatalf("content = %d items, want JSON, a labeled crop, and a note", len(result.Content))
+	}
+	if label := result.Content[1].(*mcp.TextContent).Text; label != "mobile LCP element main > img.hero: Hero image" {
+		t.Errorf("label = %q", label)
+	}
+	if _, ok := result.Content[2].(*mcp.ImageContent); !ok {
+		t.Errorf("content[2] = %T, want the LCP crop", result.Content[2])
+	}
+	if note := result.Content[3].(*mcp.TextContent).Text; !strings.Contains(note, "could not be cropped") {
+		t.Errorf("note = %q, want a crop failure for the unpositioned culprit", note)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+const (
+	defaultTreemapLimit = 10
+	maxTreemapLimit     = 50
+)
+
+// scriptTreemapInput is the input schema for the analyze_script_treemap tool.
+type scriptTreemapInput struct {
+	URL      string `json:"url"`
+	Strategy string `json:"strategy,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+}
+
+type scriptTreemapResponse struct {
+	InputURL string                   `json:"inputUrl"`
+	FinalURL string                   `json:"finalUrl,omitempty"`
+	Strategy string                   `json:"strategy"`
+	Treemap  *pagespeed.ScriptTreemap `json:"treemap"`
+}
+
+// analyzeScriptTreemap runs Lighthouse for one URL and summarizes its
+// JavaScript bundles, modules, unused bytes, and duplicated modules.
+func analyzeScriptTreemap(
+	ctx context.Context,
+	client pageAnalyzer,
+	input scriptTreemapInput,
+) (*mcp.CallToolResult, any, error) {
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultTreemapLimit
+	}
+	if limit < 1 || limit > maxTreemapLimit {
+		return nil, nil, fmt.Errorf("limit must be between 1 and %d", maxTreemapLimit)
+	}
+	result, failure, err := analyzeLab(ctx, client, input.URL, input.Strategy, "analyze_script_treemap")
+	if err != nil || failure != nil {
+		return failure, nil, err
+	}
+	treemap, err := pagespeed.ParseScriptTreemap(result.LabData, limit)
+	if err != nil {
+		return auditUnavailableResult(result, err)
+	}
+	return jsonToolResult(scriptTreemapResponse{
+		InputURL: result.Metadata.InputURL,
+		FinalURL: result.Metadata.FinalURL,
+		Strategy: result.Metadata.Strategy,
+		Treemap:  treemap,
+	})
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAnalyzeScriptTreemap_ReturnsBundlesAndDuplicates(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := analyzeScriptTreemap(context.Background(), fixtureAnalyzer{t: t}, scriptTreemapInput{
+		URL:   "https://example.test/page",
+		Limit: 1,
+	})
+	if err != nil {
+		t.Fatalf("analyzeScriptTreemap: %v", err)
+	}
+	response := decodeToolText[scriptTreemapResponse](t, result)
+	treemap := response.Treemap
+	if response.Strategy != "mobile" || treemap.Summary.Scripts != 3 || len(treemap.Bundles) != 1 ||
+		len(treemap.Modules) != 1 || len(treemap.LargestModules) != 1 {
+		t.Fatalf("response = %+v", response)
+	}
+	if treemap.Bundles[0].URL != "https://example.test/app.js" || treemap.Bundles[0].Party != "first-party" {
+		t.Errorf("largest bundle = %+v", treemap.Bundles[0])
+	}
+	if len(treemap.DuplicateModules) != 1 || treemap.DuplicateModules[0].Name != "lodash" {
+		t.Errorf("duplicate modules = %+v", treemap.DuplicateModules)
+	}
+}
+
+func TestAnalyzeScriptTreemap_RejectsInvalidLimit(t *testing.T) {
+	t.Parallel()
+
+	analyzer := &trackingAnalyzer{}
+	if _, _, err := analyzeScriptTreemap(context.Background(), analyzer, scriptTreemapInput{
+		URL:   "https://example.test/page",
+		Limit: maxTreemapLimit + 1,
+	}); err == nil {
+		t.Fatal("limit above the maximum must be rejected")
+	}
+	if calls := analyzer.calls.Load(); calls != 0 {
+		t.Errorf("API calls = %d, want 0", calls)
+	}
+}
+
+func TestAnalyzeScriptTreemap_MissingAudit_ReturnsStructuredToolError(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := analyzeScriptTreemap(context.Background(), emptyLabAnalyzer{}, scriptTreemapInput{
+		URL: "https://example.test/page",
+	})
+	assertAuditUnavailable(t, result, err)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+// simulateScoreInput is the input schema for the simulate_score tool.
+type simulateScoreInput struct {
+	URL      string             `json:"url,omitempty"`
+	Strategy string             `json:"strategy,omitempty"`
+	Metrics  map[string]float64 `json:"metrics,omitempty"`
+	Changes  map[string]float64 `json:"changes,omitempty"`
+	Targets  map[string]float64 `json:"targets,omitempty"`
+}
+
+// simulateScore projects the Lighthouse performance score for hypothetical
+// metric changes, starting from a fresh analysis of url or from raw values.
+func simulateScore(
+	ctx context.Context,
+	client pageAnalyzer,
+	input simulateScoreInput,
+) (*mcp.CallToolResult, any, error) {
+	strategy := strings.ToLower(strings.TrimSpace(input.Strategy))
+	if strategy == "" {
+		strategy = "mobile"
+	}
+	if _, err := pagespeed.ScoringCurves(strategy); err != nil {
+		return nil, nil, err
+	}
+	if strings.TrimSpace(input.URL) == "" && len(input.Metrics) == 0 {
+		return nil, nil, fmt.Errorf("either url or metrics is required")
+	}
+
+	baseline := make(map[string]float64, len(pagespeed.ScoredMetrics))
+	var reportedScore *float64
+	if strings.TrimSpace(input.URL) != "" {
+		result, failure, err := analyzeLab(ctx, client, input.URL, strategy, "simulate_score")
+		if err != nil || failure != nil {
+			return failure, nil, err
+		}
+		baseline = pagespeed.LabMetricValues(result)
+		reportedScore = result.LabData.Categories["performance"].Score
+	}
+	for name, value := range input.Metrics {
+		baseline[name] = value
+	}
+
+	simulation, err := pagespeed.SimulateScore(strategy, baseline, input.Changes, input.Targets)
+	if err != nil {
+		return nil, nil, err
+	}
+	simulation.ReportedScore = reportedScore
+	return jsonToolResult(simulation)
+}
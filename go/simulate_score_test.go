@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+func TestSimulateScore_FromRawMetrics(t *testing.T) {
+	t.Parallel()
+
+	analyzer := &trackingAnalyzer{}
+	result, _, err := simulateScore(context.Background(), analyzer, simulateScoreInput{
+		Strategy: "desktop",
+		Metrics:  map[string]float64{"fcp": 900, "speedIndex": 1300, "lcp": 3000, "tbt": 100, "cls": 0.05},
+		Targets:  map[string]float64{"lcp": 1200},
+	})
+	if err != nil {
+		t.Fatalf("simulateScore: %v", err)
+	}
+	if calls := analyzer.calls.Load(); calls != 0 {
+		t.Errorf("PSI calls = %d, want 0 for raw metrics", calls)
+	}
+
+	simulation := decodeToolText[pagespeed.ScoreSimulation](t, result)
+	if simulation.Strategy != "desktop" || simulation.ProjectedScore <= simulation.BaselineScore {
+		t.Errorf("simulation = %+v, want an improved desktop score", simulation)
+	}
+	if simulation.ReportedScore != nil {
+		t.Error("raw metric simulations have no reported score")
+	}
+}
+
+func TestSimulateScore_RequiresBaseline(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := simulateScore(context.Background(), &trackingAnalyzer{}, simulateScoreInput{}); err == nil {
+		t.Fatal("a simulation without url or metrics must be rejected")
+	}
+}
@@ -41,7 +41,7 @@ func TestStdioTransport_ServesRealSession(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListTools: %v", err)
 	}
-	if len(tools.Tools) != 4 {
-		t.Errorf("tools = %d, want 4", len(tools.Tools))
+	if len(tools.Tools) != 15 {
+		t.Errorf("tools = %d, want 15", len(tools.Tools))
 	}
 }
@@ -8,10 +8,13 @@ import (
 )
 
 var toolArrayFields = map[string][]string{
-	"analyze_page":     {"categories"},
-	"analyze_pages":    {"urls", "categories"},
-	"get_crux_data":    {"metrics"},
-	"get_crux_history": {"metrics"},
+	"analyze_page":        {"categories"},
+	"analyze_pages":       {"urls", "categories"},
+	"get_crux_data":       {"metrics", "percentiles"},
+	"get_crux_history":    {"metrics", "percentiles"},
+	"get_crux_data_batch": {"targets", "form_factors", "metrics", "percentiles"},
+	"benchmark_origins":   {"competitors"},
+	"analyze_crux_trend":  {"metrics", "windows"},
 }
 
 func coerceStringifiedArrayArgs(arrayFieldsByTool map[string][]string) mcp.Middleware {
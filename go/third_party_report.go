@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-psi-mcp/go/internal/pagespeed"
+)
+
+// thirdPartyReportInput is the input schema for the third_party_report tool.
+type thirdPartyReportInput struct {
+	URL      string `json:"url"`
+	Strategy string `json:"strategy,omitempty"`
+}
+
+type thirdPartyReportResponse struct {
+	InputURL string                      `json:"inputUrl"`
+	FinalURL string                      `json:"finalUrl,omitempty"`
+	Strategy string                      `json:"strategy"`
+	Report   *pagespeed.ThirdPartyReport `json:"report"`
+}
+
+// batchThirdParties aggregates the third-party reports of every page in an
+// analyze_pages batch analyzed with one strategy.
+type batchThirdParties struct {
+	Strategy string `json:"strategy"`
+	Pages    int    `json:"pages"`
+	*pagespeed.ThirdPartyReport
+}
+
+// thirdPartyReport runs Lighthouse for one URL and reports the transfer size,
+// main-thread time, and blocking time of each entity.
+func thirdPartyReport(
+	ctx context.Context,
+	client pageAnalyzer,
+	input thirdPartyReportInput,
+) (*mcp.CallToolResult, any, error) {
+	result, failure, err := analyzeLab(ctx, client, input.URL, input.Strategy, "third_party_report")
+	if err != nil || failure != nil {
+		return failure, nil, err
+	}
+	report, err := pagespeed.ParseThirdPartyReport(result.LabData)
+	if err != nil {
+		return auditUnavailableResult(result, err)
+	}
+	return jsonToolResult(thirdPartyReportResponse{
+		InputURL: result.Metadata.InputURL,
+		FinalURL: result.Metadata.FinalURL,
+		Strategy: result.Metadata.Strategy,
+		Report:   report,
+	})
+}
+
+// aggregateThirdParties builds one aggregated third-party report per
+// strategy from the results that have lab data.
+func aggregateThirdParties(results []*pagespeed.AnalysisResult) []batchThirdParties {
+	reports := make(map[string][]*pagespeed.ThirdPartyReport)
+	var strategies []string
+	for _, result := range results {
+		if result.LabData == nil {
+			continue
+		}
+		report, err := pagespeed.ParseThirdPartyReport(result.LabData)
+		if err != nil {
+			continue
+		}
+		strategy := result.Metadata.Strategy
+		if _, ok := reports[strategy]; !ok {
+			strategies = append(strategies, strategy)
+		}
+		reports[strategy] = append(reports[strategy], report)
+	}
+
+	aggregates := make([]batchThirdParties, 0, len(strategies))
+	for _, strategy := range strategies {
+		aggregates = append(aggregates, batchThirdParties{
+			Strategy:         strategy,
+			Pages:            len(reports[strategy]),
+			ThirdPartyReport: pagespeed.AggregateThirdPartyReports(reports[strategy]),
+		})
+	}
+	return aggregates
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestThirdPartyReport_ReturnsEntityCosts(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := thirdPartyReport(context.Background(), fixtureAnalyzer{t: t}, thirdPartyReportInput{
+		URL:      "https://example.test/page",
+		Strategy: "desktop",
+	})
+	if err != nil {
+		t.Fatalf("thirdPartyReport: %v", err)
+	}
+	response := decodeToolText[thirdPartyReportResponse](t, result)
+	if response.Strategy != "desktop" || len(response.Report.Entities) != 2 {
+		t.Fatalf("response = %+v", response)
+	}
+	gtm := response.Report.Entities[1]
+	if gtm.Name != "Google Tag Manager" || gtm.Category != "tag-manager" || gtm.MainThreadTime != 420 {
+		t.Errorf("third party = %+v", gtm)
+	}
+}
+
+func TestAnalyzePages_AggregatesThirdPartiesPerStrategy(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := analyzePages(
+		context.Background(),
+		fixtureAnalyzer{t: t},
+		[]string{"https://example.test/one", "https://example.test/two"},
+		"both",
+		[]string{"performance"},
+		"",
+	)
+	if err != nil {
+		t.Fatalf("analyzePages: %v", err)
+	}
+	response := decodeToolText[analysisResponse](t, result)
+	if len(response.ThirdParties) != 2 {
+		t.Fatalf("third parties = %+v, want mobile and desktop", response.ThirdParties)
+	}
+	for _, aggregate := range response.ThirdParties {
+		if aggregate.Pages != 2 || aggregate.ThirdPartyReport == nil || len(aggregate.Entities) != 2 {
+			t.Fatalf("%s aggregate = %+v", aggregate.Strategy, aggregate)
+		}
+		gtm := aggregate.Entities[1]
+		if gtm.Name != "Google Tag Manager" || gtm.Pages != 2 || gtm.Requests != 2 || gtm.MainThreadTime != 840 {
+			t.Errorf("%s third party = %+v", aggregate.Strategy, gtm)
+		}
+	}
+}
+
+func TestThirdPartyReport_MissingAudit_ReturnsStructuredToolError(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := thirdPartyReport(context.Background(), emptyLabAnalyzer{}, thirdPartyReportInput{
+		URL: "https://example.test/page",
+	})
+	assertAuditUnavailable(t, result, err)
+}